@@ -0,0 +1,16 @@
+package cache
+
+import (
+	"github.com/packwiz/packwiz/cmd"
+	"github.com/spf13/cobra"
+)
+
+// cacheCmd represents the base command when called without any subcommands
+var cacheCmd = &cobra.Command{
+	Use:   "cache",
+	Short: "Manage the cache of downloaded files",
+}
+
+func init() {
+	cmd.Add(cacheCmd)
+}
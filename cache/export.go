@@ -0,0 +1,139 @@
+package cache
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/packwiz/packwiz/core"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// exportCmd represents the export command
+var exportCmd = &cobra.Command{
+	Use:   "export [archive]",
+	Short: "Export the cached files that the pack needs to an archive, to be imported with packwiz cache import",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		side := viper.GetString("cache.export.side")
+		if side != "" && side != core.UniversalSide && side != core.ClientSide && side != core.ServerSide {
+			fmt.Printf("Invalid side %q; must be one of both, client or server\n", side)
+			os.Exit(1)
+		}
+
+		fmt.Println("Loading modpack...")
+		pack, err := core.LoadPack()
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		mods, err := loadPackMods(pack, side)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		cacheIndex, err := core.LoadCacheIndex()
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+
+		var handles []*core.CacheIndexHandle
+		var missing []*core.Mod
+		found := make(map[string]bool)
+		for _, mod := range mods {
+			handle, err := cacheIndex.GetHandleFromHashForce(mod.Download.HashFormat, mod.Download.Hash)
+			if err != nil {
+				fmt.Printf("Failed to lookup %s in cache: %v\n", mod.Name, err)
+				os.Exit(1)
+			}
+			if handle == nil {
+				missing = append(missing, mod)
+				continue
+			}
+			if !found[handle.RelPath()] {
+				found[handle.RelPath()] = true
+				handles = append(handles, handle)
+			}
+		}
+		// Hashes may have been calculated while looking up files
+		err = cacheIndex.Save()
+		if err != nil {
+			fmt.Printf("Error saving cache index: %v\n", err)
+			os.Exit(1)
+		}
+		if len(missing) > 0 {
+			fmt.Printf("%d file(s) are missing from the cache; run packwiz cache fetch to download them:\n", len(missing))
+			for _, mod := range missing {
+				fmt.Printf("%s (%s)\n", mod.Name, mod.FileName)
+			}
+			os.Exit(1)
+		}
+
+		expFile, err := os.Create(args[0])
+		if err != nil {
+			fmt.Printf("Failed to create archive file: %v\n", err)
+			os.Exit(1)
+		}
+		exp := zip.NewWriter(expFile)
+		archiveIndex := cacheIndex.NewCacheIndexSubset()
+		for _, handle := range handles {
+			err = addToArchive(exp, handle)
+			if err != nil {
+				fmt.Printf("Failed to add %s to archive: %v\n", handle.RelPath(), err)
+				_ = exp.Close()
+				_ = expFile.Close()
+				os.Exit(1)
+			}
+			archiveIndex.AddEntry(handle.Hashes)
+		}
+
+		indexFile, err := exp.Create("index.json")
+		if err == nil {
+			err = json.NewEncoder(indexFile).Encode(archiveIndex)
+		}
+		if err != nil {
+			fmt.Printf("Failed to add cache index to archive: %v\n", err)
+			_ = exp.Close()
+			_ = expFile.Close()
+			os.Exit(1)
+		}
+		err = exp.Close()
+		if err != nil {
+			fmt.Printf("Failed to write archive: %v\n", err)
+			_ = expFile.Close()
+			os.Exit(1)
+		}
+		err = expFile.Close()
+		if err != nil {
+			fmt.Printf("Failed to write archive: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("%d cached file(s) exported to %s\n", len(handles), args[0])
+	},
+}
+
+func addToArchive(exp *zip.Writer, handle *core.CacheIndexHandle) error {
+	file, err := handle.Open()
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	// Cached files are already compressed (or are small), so they are stored as-is
+	w, err := exp.CreateHeader(&zip.FileHeader{Name: handle.RelPath(), Method: zip.Store})
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(w, file)
+	return err
+}
+
+func init() {
+	cacheCmd.AddCommand(exportCmd)
+
+	exportCmd.Flags().String("side", "", "Only export files installed on this side (client or server), rather than every file")
+	_ = viper.BindPFlag("cache.export.side", exportCmd.Flags().Lookup("side"))
+}
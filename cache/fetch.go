@@ -0,0 +1,103 @@
+package cache
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/packwiz/packwiz/cmdshared"
+	"github.com/packwiz/packwiz/core"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// fetchCmd represents the fetch command
+var fetchCmd = &cobra.Command{
+	Use:   "fetch",
+	Short: "Download every file the pack needs into the cache",
+	Long: `Download every file the pack needs into the cache, so the pack can be exported in offline mode.
+Files stored in the pack's local store don't need to be cached, so they are skipped.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		side := viper.GetString("cache.fetch.side")
+		if side != "" && side != core.UniversalSide && side != core.ClientSide && side != core.ServerSide {
+			fmt.Printf("Invalid side %q; must be one of both, client or server\n", side)
+			os.Exit(1)
+		}
+
+		fmt.Println("Loading modpack...")
+		pack, err := core.LoadPack()
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		mods, err := loadPackMods(pack, side)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+
+		fmt.Printf("Retrieving %v external files...\n", len(mods))
+		session, err := core.CreateDownloadSession(mods, []string{})
+		if err != nil {
+			fmt.Printf("Error retrieving external files: %v\n", err)
+			os.Exit(1)
+		}
+		cmdshared.ListManualDownloads(session)
+
+		failed := 0
+		for dl := range session.StartDownloads() {
+			if dl.Error != nil {
+				fmt.Printf("Download of %s (%s) failed: %v\n", dl.Mod.Name, dl.Mod.FileName, dl.Error)
+				failed++
+				continue
+			}
+			for _, warning := range dl.Warnings {
+				fmt.Printf("Warning for %s (%s): %v\n", dl.Mod.Name, dl.Mod.FileName, warning)
+			}
+			_ = dl.File.Close()
+		}
+		err = session.SaveIndex()
+		if err != nil {
+			fmt.Printf("Error saving cache index: %v\n", err)
+			os.Exit(1)
+		}
+		if failed > 0 || len(session.GetManualDownloads()) > 0 {
+			fmt.Printf("%d file(s) couldn't be added to the cache\n", failed+len(session.GetManualDownloads()))
+			os.Exit(1)
+		}
+		fmt.Printf("%d file(s) are in the cache!\n", len(mods))
+	},
+}
+
+// loadPackMods loads the metadata files of a pack that are installed on the given side (or all of them, if side is
+// empty), excluding files stored in the pack's local store
+func loadPackMods(pack core.Pack, side string) ([]*core.Mod, error) {
+	index, err := pack.LoadIndex()
+	if err != nil {
+		return nil, err
+	}
+	fmt.Println("Reading metadata files...")
+	mods, err := index.LoadAllMods()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read metadata files: %w", err)
+	}
+
+	var filtered []*core.Mod
+	for _, mod := range mods {
+		if (mod.Download.Mode == core.ModeURL || mod.Download.Mode == "") && core.IsLocalURL(mod.Download.URL) {
+			continue
+		}
+		if side != "" && side != core.UniversalSide && mod.Side != core.UniversalSide && mod.Side != core.EmptySide && mod.Side != side {
+			continue
+		}
+		filtered = append(filtered, mod)
+	}
+	return filtered, nil
+}
+
+func init() {
+	cacheCmd.AddCommand(fetchCmd)
+
+	fetchCmd.Flags().String("side", "", "Only fetch files installed on this side (client or server), rather than every file")
+	_ = viper.BindPFlag("cache.fetch.side", fetchCmd.Flags().Lookup("side"))
+}
@@ -0,0 +1,187 @@
+package cache
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/packwiz/packwiz/core"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// gcCmd represents the gc command
+var gcCmd = &cobra.Command{
+	Use:   "gc",
+	Short: "Remove files from the cache that haven't been used recently, or to limit the size of the cache",
+	Long: `Remove files from the cache that haven't been used recently, or to limit the size of the cache.
+The least recently used files are removed first. Files used by the packs given with --keep-pack are never removed.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		var policy core.CacheEvictionPolicy
+		var err error
+		if s := viper.GetString("cache.gc.max-size"); s != "" {
+			policy.MaxSize, err = parseSize(s)
+			if err != nil {
+				fmt.Printf("Invalid maximum size: %v\n", err)
+				os.Exit(1)
+			}
+		}
+		if s := viper.GetString("cache.gc.max-age"); s != "" {
+			policy.MaxAge, err = parseAge(s)
+			if err != nil {
+				fmt.Printf("Invalid maximum age: %v\n", err)
+				os.Exit(1)
+			}
+		}
+		if policy.MaxSize <= 0 && policy.MaxAge <= 0 {
+			fmt.Println("A maximum size (--max-size) or age (--max-age) must be given")
+			os.Exit(1)
+		}
+
+		cacheIndex, err := core.LoadCacheIndex()
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		for _, packFile := range viper.GetStringSlice("cache.gc.keep-pack") {
+			fmt.Printf("Loading %s...\n", packFile)
+			// The options of each pack aren't read, so they don't apply to the packs loaded after it
+			viper.Set("pack-file", packFile)
+			pack, err := core.LoadPackFile(packFile)
+			if err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+			mods, err := loadPackMods(pack, "")
+			if err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+			for _, mod := range mods {
+				handle, err := cacheIndex.GetHandleFromHashForce(mod.Download.HashFormat, mod.Download.Hash)
+				if err != nil {
+					fmt.Printf("Failed to lookup %s in cache: %v\n", mod.Name, err)
+					os.Exit(1)
+				}
+				if handle != nil {
+					policy.Keep = append(policy.Keep, handle)
+				}
+			}
+		}
+
+		evictable, size, err := cacheIndex.FindEvictable(policy)
+		if err != nil {
+			fmt.Printf("Failed to read cache: %v\n", err)
+			os.Exit(1)
+		}
+		if viper.GetBool("cache.gc.dry-run") {
+			for _, handle := range evictable {
+				fmt.Println(handle.Path())
+			}
+			fmt.Printf("%d file(s) would be removed, freeing %s\n", len(evictable), formatSize(size))
+			return
+		}
+
+		removed := 0
+		var freed int64
+		for _, handle := range evictable {
+			// Entries whose files are already missing don't free any space
+			var fileSize int64
+			if stats, err := os.Stat(handle.Path()); err == nil {
+				fileSize = stats.Size()
+			}
+			err = handle.Remove()
+			if err != nil {
+				fmt.Println(err)
+				continue
+			}
+			removed++
+			freed += fileSize
+		}
+		err = cacheIndex.Save()
+		if err != nil {
+			fmt.Printf("Error saving cache index: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("%d file(s) removed, freeing %s\n", removed, formatSize(freed))
+		if removed < len(evictable) {
+			os.Exit(1)
+		}
+	},
+}
+
+var sizeUnits = []struct {
+	suffix string
+	size   int64
+}{
+	// Longest suffixes first, so that e.g. KiB isn't parsed as B
+	{"KiB", 1 << 10}, {"MiB", 1 << 20}, {"GiB", 1 << 30}, {"TiB", 1 << 40},
+	{"KB", 1e3}, {"MB", 1e6}, {"GB", 1e9}, {"TB", 1e12},
+	{"K", 1 << 10}, {"M", 1 << 20}, {"G", 1 << 30}, {"T", 1 << 40},
+	{"B", 1},
+}
+
+// parseSize parses a size in bytes, with an optional unit (e.g. 500MB, 10GiB, 2G)
+func parseSize(s string) (int64, error) {
+	s = strings.TrimSpace(s)
+	multiplier := int64(1)
+	for _, unit := range sizeUnits {
+		if strings.HasSuffix(strings.ToUpper(s), strings.ToUpper(unit.suffix)) {
+			s = strings.TrimSpace(s[:len(s)-len(unit.suffix)])
+			multiplier = unit.size
+			break
+		}
+	}
+	value, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, err
+	}
+	if value < 0 {
+		return 0, errors.New("size must not be negative")
+	}
+	return int64(value * float64(multiplier)), nil
+}
+
+// parseAge parses a duration, additionally allowing a number of days (e.g. 30d)
+func parseAge(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	if strings.HasSuffix(s, "d") {
+		days, err := strconv.ParseFloat(strings.TrimSuffix(s, "d"), 64)
+		if err != nil {
+			return 0, err
+		}
+		return time.Duration(days * float64(24*time.Hour)), nil
+	}
+	return time.ParseDuration(s)
+}
+
+func formatSize(size int64) string {
+	units := []string{"B", "KiB", "MiB", "GiB", "TiB"}
+	value := float64(size)
+	i := 0
+	for value >= 1024 && i < len(units)-1 {
+		value /= 1024
+		i++
+	}
+	if i == 0 {
+		return fmt.Sprintf("%d B", size)
+	}
+	return fmt.Sprintf("%.1f %s", value, units[i])
+}
+
+func init() {
+	cacheCmd.AddCommand(gcCmd)
+
+	gcCmd.Flags().String("max-size", "", "The maximum total size of the cache (e.g. 500MB or 10GiB)")
+	_ = viper.BindPFlag("cache.gc.max-size", gcCmd.Flags().Lookup("max-size"))
+	gcCmd.Flags().String("max-age", "", "The maximum time since a file was last used (e.g. 30d or 12h)")
+	_ = viper.BindPFlag("cache.gc.max-age", gcCmd.Flags().Lookup("max-age"))
+	gcCmd.Flags().StringSlice("keep-pack", nil, "The pack.toml file of a pack whose files should never be removed (can be given multiple times)")
+	_ = viper.BindPFlag("cache.gc.keep-pack", gcCmd.Flags().Lookup("keep-pack"))
+	gcCmd.Flags().Bool("dry-run", false, "List the files that would be removed, without removing them")
+	_ = viper.BindPFlag("cache.gc.dry-run", gcCmd.Flags().Lookup("dry-run"))
+}
@@ -0,0 +1,62 @@
+package cache
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseSize(t *testing.T) {
+	tests := []struct {
+		s       string
+		want    int64
+		wantErr bool
+	}{
+		{"1024", 1024, false},
+		{"500B", 500, false},
+		{"500MB", 500e6, false},
+		{"10GiB", 10 << 30, false},
+		{"2G", 2 << 30, false},
+		{"1.5 KiB", 1536, false},
+		{"1kb", 1000, false},
+		{" 3 TB ", 3e12, false},
+		{"-1MB", 0, true},
+		{"MB", 0, true},
+		{"ten", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := parseSize(tt.s)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseSize(%q) error = %v, wantErr %v", tt.s, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("parseSize(%q) = %d, want %d", tt.s, got, tt.want)
+		}
+	}
+}
+
+func TestParseAge(t *testing.T) {
+	tests := []struct {
+		s       string
+		want    time.Duration
+		wantErr bool
+	}{
+		{"30d", 30 * 24 * time.Hour, false},
+		{"1.5d", 36 * time.Hour, false},
+		{"12h", 12 * time.Hour, false},
+		{"90m", 90 * time.Minute, false},
+		{"d", 0, true},
+		{"30", 0, true},
+		{"a week", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := parseAge(tt.s)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseAge(%q) error = %v, wantErr %v", tt.s, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("parseAge(%q) = %v, want %v", tt.s, got, tt.want)
+		}
+	}
+}
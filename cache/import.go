@@ -0,0 +1,88 @@
+package cache
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/packwiz/packwiz/core"
+	"github.com/spf13/cobra"
+)
+
+// importCmd represents the import command
+var importCmd = &cobra.Command{
+	Use:   "import [archive]",
+	Short: "Import cached files from an archive created with packwiz cache export",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		archive, err := zip.OpenReader(args[0])
+		if err != nil {
+			fmt.Printf("Failed to open archive: %v\n", err)
+			os.Exit(1)
+		}
+		defer archive.Close()
+
+		var archiveIndex core.CacheIndex
+		indexFile, err := archive.Open("index.json")
+		if err == nil {
+			err = json.NewDecoder(indexFile).Decode(&archiveIndex)
+			_ = indexFile.Close()
+		}
+		if err == nil {
+			err = archiveIndex.Validate()
+		}
+		if err != nil {
+			fmt.Printf("Failed to read cache index from archive: %v\n", err)
+			os.Exit(1)
+		}
+
+		cacheIndex, err := core.LoadCacheIndex()
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		if archiveIndex.Version > cacheIndex.Version {
+			fmt.Printf("The archive was exported by a newer version of packwiz (cache version %v)\n", archiveIndex.Version)
+			os.Exit(1)
+		}
+
+		imported, existing, failed := 0, 0, 0
+		for _, entry := range archiveIndex.GetEntries() {
+			file, err := archive.Open(entry.RelPath())
+			if err != nil {
+				fmt.Printf("Failed to read %s from archive: %v\n", entry.RelPath(), err)
+				failed++
+				continue
+			}
+			added, err := cacheIndex.ImportFile(entry.Hashes, file)
+			_ = file.Close()
+			if err != nil {
+				fmt.Printf("Failed to import %s: %v\n", entry.RelPath(), err)
+				failed++
+				continue
+			}
+			if added {
+				imported++
+			} else {
+				existing++
+			}
+		}
+
+		// Save the files that were imported, even if others failed
+		err = cacheIndex.Save()
+		if err != nil {
+			fmt.Printf("Error saving cache index: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("%d file(s) imported (%d already cached)\n", imported, existing)
+		if failed > 0 {
+			fmt.Printf("%d file(s) failed to import\n", failed)
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	cacheCmd.AddCommand(importCmd)
+}
@@ -0,0 +1,180 @@
+package cache
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/packwiz/packwiz/cmdshared"
+	"github.com/packwiz/packwiz/core"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// verifyCmd represents the verify command
+var verifyCmd = &cobra.Command{
+	Use:   "verify",
+	Short: "Check the files in the cache against their hashes, removing corrupt files",
+	Long: `Check the files in the cache against their hashes, removing corrupt files.
+Index entries without a file are removed, and files that aren't in the index are added back if they match the hash
+they are named by (or removed otherwise). With --repair, removed files used by the current pack (or the packs given
+with --pack) are downloaded again.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		quarantine := viper.GetBool("cache.verify.quarantine")
+		cacheIndex, err := core.LoadCacheIndex()
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+
+		fmt.Println("Verifying cached files...")
+		result, err := cacheIndex.Verify()
+		if err != nil {
+			fmt.Printf("Failed to verify cache: %v\n", err)
+			os.Exit(1)
+		}
+
+		failed := false
+		var removed []*core.CacheIndexHandle
+		for _, handle := range result.Corrupt {
+			fmt.Printf("Corrupt file: %s\n", handle.Path())
+			if quarantine {
+				err = handle.Quarantine()
+			} else {
+				err = handle.Remove()
+			}
+			if err != nil {
+				fmt.Println(err)
+				failed = true
+				continue
+			}
+			removed = append(removed, handle)
+		}
+		for _, handle := range result.Missing {
+			fmt.Printf("Missing file: %s\n", handle.Path())
+			err = handle.Remove()
+			if err != nil {
+				fmt.Println(err)
+				failed = true
+				continue
+			}
+			removed = append(removed, handle)
+		}
+		recovered := 0
+		for _, path := range result.Orphaned {
+			ok, err := cacheIndex.ImportOrphan(path)
+			if err != nil {
+				fmt.Printf("Failed to read orphaned file %s: %v\n", path, err)
+				failed = true
+				continue
+			}
+			if ok {
+				fmt.Printf("Orphaned file added back to the index: %s\n", path)
+				recovered++
+				continue
+			}
+			fmt.Printf("Corrupt orphaned file: %s\n", path)
+			if quarantine {
+				err = cacheIndex.QuarantineFile(path)
+			} else {
+				err = os.Remove(path)
+			}
+			if err != nil {
+				fmt.Println(err)
+				failed = true
+			}
+		}
+
+		err = cacheIndex.Save()
+		if err != nil {
+			fmt.Printf("Error saving cache index: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("Checked %d file(s): %d corrupt, %d missing, %d orphaned (%d added back to the index)\n",
+			result.Checked, len(result.Corrupt), len(result.Missing), len(result.Orphaned), recovered)
+		if quarantine && len(result.Corrupt)+len(result.Orphaned)-recovered > 0 {
+			fmt.Printf("Corrupt files were moved to %s in the cache folder\n", core.CacheQuarantineFolder)
+		}
+
+		if viper.GetBool("cache.verify.repair") && len(removed) > 0 {
+			if !repairFiles(removed) {
+				failed = true
+			}
+		}
+		if failed {
+			os.Exit(1)
+		}
+	},
+}
+
+// repairFiles downloads the files of the given (removed) cache entries again, if they are used by the packs to repair
+func repairFiles(removed []*core.CacheIndexHandle) bool {
+	packFiles := viper.GetStringSlice("cache.verify.pack")
+	if len(packFiles) == 0 {
+		packFiles = []string{viper.GetString("pack-file")}
+	}
+
+	var mods []*core.Mod
+	for _, packFile := range packFiles {
+		fmt.Printf("Loading %s...\n", packFile)
+		viper.Set("pack-file", packFile)
+		pack, err := core.LoadPack()
+		if err != nil {
+			fmt.Println(err)
+			return false
+		}
+		packMods, err := loadPackMods(pack, "")
+		if err != nil {
+			fmt.Println(err)
+			return false
+		}
+		for _, mod := range packMods {
+			for _, handle := range removed {
+				if strings.EqualFold(handle.Hashes[mod.Download.HashFormat], mod.Download.Hash) {
+					mods = append(mods, mod)
+					break
+				}
+			}
+		}
+	}
+	if len(mods) == 0 {
+		fmt.Println("None of the removed files are used by the pack")
+		return true
+	}
+
+	fmt.Printf("Downloading %d file(s) again...\n", len(mods))
+	session, err := core.CreateDownloadSession(mods, []string{})
+	if err != nil {
+		fmt.Printf("Error retrieving external files: %v\n", err)
+		return false
+	}
+	cmdshared.ListManualDownloads(session)
+	ok := len(session.GetManualDownloads()) == 0
+	for dl := range session.StartDownloads() {
+		if dl.Error != nil {
+			fmt.Printf("Download of %s (%s) failed: %v\n", dl.Mod.Name, dl.Mod.FileName, dl.Error)
+			ok = false
+			continue
+		}
+		_ = dl.File.Close()
+		fmt.Printf("Repaired %s (%s)\n", dl.Mod.Name, dl.Mod.FileName)
+	}
+	err = session.SaveIndex()
+	if err != nil {
+		fmt.Printf("Error saving cache index: %v\n", err)
+		return false
+	}
+	return ok
+}
+
+func init() {
+	cacheCmd.AddCommand(verifyCmd)
+
+	verifyCmd.Flags().Bool("repair", false, "Download removed files again, if they are used by the pack")
+	_ = viper.BindPFlag("cache.verify.repair", verifyCmd.Flags().Lookup("repair"))
+	verifyCmd.Flags().StringSlice("pack", nil, "The pack.toml file of a pack to repair files for, rather than the current pack (can be given multiple times)")
+	_ = viper.BindPFlag("cache.verify.pack", verifyCmd.Flags().Lookup("pack"))
+	verifyCmd.Flags().Bool("quarantine", false, "Move corrupt files to the quarantine folder in the cache, rather than deleting them")
+	_ = viper.BindPFlag("cache.verify.quarantine", verifyCmd.Flags().Lookup("quarantine"))
+}
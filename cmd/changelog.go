@@ -0,0 +1,142 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/packwiz/packwiz/cmdshared"
+	"github.com/packwiz/packwiz/core"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// changelogCmd represents the changelog command
+var changelogCmd = &cobra.Command{
+	Use:   "changelog",
+	Short: "Generate a Markdown changelog of the modpack since a git ref, including release notes of updated files",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		from := viper.GetString("changelog.from")
+		if from == "" {
+			fmt.Println("Must specify a git ref (or pack.toml file) to generate the changelog from with --from")
+			os.Exit(1)
+		}
+
+		oldState, cleanupOld, err := cmdshared.LoadPackStateFromArg(from)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		defer cleanupOld()
+
+		var newState cmdshared.PackState
+		if to := viper.GetString("changelog.to"); to != "" {
+			var cleanupNew func()
+			newState, cleanupNew, err = cmdshared.LoadPackStateFromArg(to)
+			if err != nil {
+				cleanupOld()
+				fmt.Println(err)
+				os.Exit(1)
+			}
+			defer cleanupNew()
+		} else {
+			newState, err = cmdshared.LoadPackState(viper.GetString("pack-file"))
+			if err != nil {
+				cleanupOld()
+				fmt.Println(err)
+				os.Exit(1)
+			}
+		}
+
+		diff := cmdshared.DiffPacks(oldState, newState)
+
+		changelog := formatChangelog(diff, getChangelogs)
+		if changelog == "" {
+			_, _ = fmt.Fprintln(os.Stderr, "No changes found!")
+			return
+		}
+		fmt.Print(changelog)
+	},
+}
+
+// formatChangelog formats the differences between two versions of a pack as a Markdown changelog, using
+// getChangelogs to retrieve the release notes of updated files; returns an empty string if nothing changed
+func formatChangelog(diff cmdshared.PackDiff, getChangelogs func(cmdshared.ModChange) ([]core.Changelog, error)) string {
+	var sections []string
+	if len(diff.VersionChanges) > 0 {
+		section := "## Versions\n"
+		for _, v := range diff.VersionChanges {
+			section += "- " + describeVersionChange(v) + "\n"
+		}
+		sections = append(sections, section)
+	}
+	if len(diff.Added) > 0 {
+		section := "## Added\n"
+		for _, v := range diff.Added {
+			section += "- " + v.Name + "\n"
+		}
+		sections = append(sections, section)
+	}
+	if len(diff.Removed) > 0 {
+		section := "## Removed\n"
+		for _, v := range diff.Removed {
+			section += "- " + v.Name + "\n"
+		}
+		sections = append(sections, section)
+	}
+
+	var updated []string
+	for _, change := range diff.Changed {
+		if !change.VersionChanged {
+			continue
+		}
+		entry := fmt.Sprintf("### %s\n`%s` -> `%s`\n", change.New.Name, change.Old.FileName, change.New.FileName)
+		changelogs, err := getChangelogs(change)
+		if err != nil {
+			// Print to stderr so the Markdown output can be redirected to a file
+			_, _ = fmt.Fprintf(os.Stderr, "Failed to get changelog for %s: %v\n", change.New.Name, err)
+		}
+		for _, v := range changelogs {
+			text := strings.TrimSpace(v.Text)
+			if text == "" {
+				text = "*No changelog provided*"
+			}
+			entry += "\n#### " + v.Version + "\n" + text + "\n"
+		}
+		updated = append(updated, entry)
+	}
+	if len(updated) > 0 {
+		sections = append(sections, "## Updated\n\n"+strings.Join(updated, "\n"))
+	}
+
+	if len(sections) == 0 {
+		return ""
+	}
+	return "# Changelog\n\n" + strings.Join(sections, "\n")
+}
+
+// getChangelogs retrieves the release notes for an updated file, if its update system supports it
+func getChangelogs(change cmdshared.ModChange) ([]core.Changelog, error) {
+	if change.OldIdentity.Updater == "" || change.OldIdentity.Updater != change.NewIdentity.Updater {
+		return nil, nil
+	}
+	updater, ok := core.Updaters[change.NewIdentity.Updater]
+	if !ok {
+		return nil, nil
+	}
+	getter, ok := updater.(core.ChangelogGetter)
+	if !ok {
+		return nil, nil
+	}
+	return getter.GetChangelogs(change.Old, change.New)
+}
+
+func init() {
+	rootCmd.AddCommand(changelogCmd)
+
+	changelogCmd.Flags().String("from", "", "The git ref (or pack.toml file) of the previous version of the modpack")
+	_ = viper.BindPFlag("changelog.from", changelogCmd.Flags().Lookup("from"))
+	changelogCmd.Flags().String("to", "", "The git ref (or pack.toml file) of the new version of the modpack (defaults to the current pack)")
+	_ = viper.BindPFlag("changelog.to", changelogCmd.Flags().Lookup("to"))
+}
@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/packwiz/packwiz/cmdshared"
+	"github.com/packwiz/packwiz/core"
+)
+
+func TestFormatChangelog(t *testing.T) {
+	if changelog := formatChangelog(cmdshared.PackDiff{}, nil); changelog != "" {
+		t.Errorf("Expected no changelog without changes, got %q", changelog)
+	}
+
+	diff := cmdshared.PackDiff{
+		VersionChanges: []cmdshared.VersionChange{{Component: "minecraft", Old: "1.20.1", New: "1.20.4"}},
+		Added:          []*core.Mod{{Name: "New Mod"}},
+		Removed:        []*core.Mod{{Name: "Old Mod"}},
+		Changed: []cmdshared.ModChange{{
+			Old:            &core.Mod{Name: "Updated", FileName: "updated-1.0.jar"},
+			New:            &core.Mod{Name: "Updated", FileName: "updated-1.2.jar"},
+			VersionChanged: true,
+		}, {
+			Old:         &core.Mod{Name: "Moved", FileName: "moved.jar"},
+			New:         &core.Mod{Name: "Moved", FileName: "moved.jar"},
+			SideChanged: true,
+		}, {
+			Old:            &core.Mod{Name: "Unknown", FileName: "unknown-1.jar"},
+			New:            &core.Mod{Name: "Unknown", FileName: "unknown-2.jar"},
+			VersionChanged: true,
+		}},
+	}
+	getChangelogs := func(change cmdshared.ModChange) ([]core.Changelog, error) {
+		if change.New.Name == "Unknown" {
+			return nil, errors.New("not supported")
+		}
+		return []core.Changelog{{Version: "1.2", Text: "  Fixed bugs\n"}, {Version: "1.1", Text: ""}}, nil
+	}
+
+	want := "# Changelog\n\n" +
+		"## Versions\n- Minecraft 1.20.1 -> 1.20.4\n\n" +
+		"## Added\n- New Mod\n\n" +
+		"## Removed\n- Old Mod\n\n" +
+		"## Updated\n\n" +
+		"### Updated\n`updated-1.0.jar` -> `updated-1.2.jar`\n\n#### 1.2\nFixed bugs\n\n#### 1.1\n*No changelog provided*\n\n" +
+		"### Unknown\n`unknown-1.jar` -> `unknown-2.jar`\n"
+	if got := formatChangelog(diff, getChangelogs); got != want {
+		t.Errorf("Unexpected changelog:\n%s\nwant:\n%s", got, want)
+	}
+}
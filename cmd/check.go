@@ -0,0 +1,120 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/packwiz/packwiz/cmdshared"
+	"github.com/packwiz/packwiz/core"
+	"github.com/spf13/cobra"
+)
+
+// checkCmd represents the check command
+var checkCmd = &cobra.Command{
+	Use:   "check",
+	Short: "Check that dependencies are satisfied and files are compatible with the pack's loaders and Minecraft versions",
+	Long: `Check that dependencies are satisfied and files are compatible with the pack's loaders and Minecraft versions.
+Reports missing required dependencies, incompatible files, and files whose loaders or Minecraft versions don't match
+the pack. Exits with a non-zero status if any problems are found.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		fmt.Println("Loading modpack...")
+		pack, err := core.LoadPack()
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		index, err := pack.LoadIndex()
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		mcVersions, err := pack.GetSupportedMCVersions()
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		compatibleLoaders := pack.GetCompatibleLoaders()
+
+		fmt.Println("Reading metadata files...")
+		mods, err := index.LoadAllMods()
+		if err != nil {
+			fmt.Printf("Failed to read metadata files: %v\n", err)
+			os.Exit(1)
+		}
+
+		installed := cmdshared.NewDependencyResolver(mods)
+
+		fmt.Println("Checking dependencies...")
+		var problems []string
+		deps, errs := cmdshared.GetDependencyInfo(mods, pack)
+		for _, err := range errs {
+			problems = append(problems, fmt.Sprintf("Error: %v", err))
+		}
+		reportedIncompatible := make(map[string]bool)
+		for _, modData := range mods {
+			dep, ok := deps[modData]
+			if !ok {
+				continue
+			}
+			info := dep.Info
+			if info.Error != nil {
+				problems = append(problems, fmt.Sprintf("Failed to check dependencies for %s: %v", modData.Name, info.Error))
+				continue
+			}
+			for _, v := range info.Required {
+				if _, ok := installed.Find(dep.Updater, v.ProjectID); !ok {
+					problems = append(problems, fmt.Sprintf("%s requires %s, which is not in the pack", modData.Name, v.Name))
+				}
+			}
+			for _, v := range info.Incompatible {
+				if other, ok := installed.Find(dep.Updater, v.ProjectID); ok {
+					// Only report each pair once, if both files declare the incompatibility
+					names := []string{modData.Name, other.Name}
+					slices.Sort(names)
+					key := strings.Join(names, "\x00")
+					if !reportedIncompatible[key] {
+						reportedIncompatible[key] = true
+						problems = append(problems, fmt.Sprintf("%s is incompatible with %s", modData.Name, other.Name))
+					}
+				}
+			}
+
+			var fileLoaders []string
+			for _, loader := range info.Loaders {
+				if _, ok := core.ModLoaders[loader]; ok {
+					fileLoaders = append(fileLoaders, loader)
+				}
+			}
+			if len(fileLoaders) > 0 && !slices.ContainsFunc(fileLoaders, func(loader string) bool {
+				return slices.Contains(compatibleLoaders, loader)
+			}) {
+				problems = append(problems, fmt.Sprintf("%s only supports %s, which is not compatible with the pack's loaders (%s)",
+					modData.Name, strings.Join(fileLoaders, ", "), strings.Join(compatibleLoaders, ", ")))
+			}
+			if len(info.GameVersions) > 0 && !slices.ContainsFunc(info.GameVersions, func(version string) bool {
+				return slices.Contains(mcVersions, version)
+			}) {
+				problems = append(problems, fmt.Sprintf("%s doesn't support any of the pack's Minecraft versions (%s)",
+					modData.Name, strings.Join(mcVersions, ", ")))
+			}
+		}
+
+		if len(problems) == 0 {
+			fmt.Println("No problems found!")
+			return
+		}
+		slices.Sort(problems)
+		fmt.Printf("Found %d problem(s):\n", len(problems))
+		for _, v := range problems {
+			fmt.Println(v)
+		}
+		os.Exit(1)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(checkCmd)
+}
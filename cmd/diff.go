@@ -0,0 +1,189 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/packwiz/packwiz/cmdshared"
+	"github.com/packwiz/packwiz/core"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// diffCmd represents the diff command
+var diffCmd = &cobra.Command{
+	Use:   "diff [old pack.toml or git ref] [new pack.toml or git ref]",
+	Short: "Show the changes between two versions of the modpack",
+	Long: `Show the changes between two versions of the modpack: added, removed and updated files, side and option changes,
+Minecraft and loader version changes, and changed non-metadata files. Each version can be a pack.toml file, or a git ref
+to read the current pack from; if only one is given, it is compared with the current pack.`,
+	Args: cobra.RangeArgs(1, 2),
+	Run: func(cmd *cobra.Command, args []string) {
+		format := viper.GetString("diff.format")
+		if format != "text" && format != "markdown" {
+			fmt.Printf("Unknown output format %s (must be text or markdown)\n", format)
+			os.Exit(1)
+		}
+
+		oldState, cleanupOld, err := cmdshared.LoadPackStateFromArg(args[0])
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		defer cleanupOld()
+
+		var newState cmdshared.PackState
+		if len(args) > 1 {
+			var cleanupNew func()
+			newState, cleanupNew, err = cmdshared.LoadPackStateFromArg(args[1])
+			if err != nil {
+				cleanupOld()
+				fmt.Println(err)
+				os.Exit(1)
+			}
+			defer cleanupNew()
+		} else {
+			newState, err = cmdshared.LoadPackState(viper.GetString("pack-file"))
+			if err != nil {
+				cleanupOld()
+				fmt.Println(err)
+				os.Exit(1)
+			}
+		}
+
+		diff := cmdshared.DiffPacks(oldState, newState)
+		if diff.IsEmpty() {
+			fmt.Println("No changes found!")
+			return
+		}
+		if format == "markdown" {
+			printDiffMarkdown(diff)
+		} else {
+			printDiffText(diff)
+		}
+	},
+}
+
+func describeVersionChange(v cmdshared.VersionChange) string {
+	name := core.ComponentToFriendlyName(v.Component)
+	if v.Old == "" {
+		return fmt.Sprintf("%s %s added", name, v.New)
+	} else if v.New == "" {
+		return fmt.Sprintf("%s %s removed", name, v.Old)
+	}
+	return fmt.Sprintf("%s %s -> %s", name, v.Old, v.New)
+}
+
+func describeModChange(change cmdshared.ModChange) []string {
+	var changes []string
+	if change.VersionChanged {
+		changes = append(changes, change.Old.FileName+" -> "+change.New.FileName)
+	}
+	if change.SideChanged {
+		changes = append(changes, "side: "+sideString(change.Old.Side)+" -> "+sideString(change.New.Side))
+	}
+	if change.OptionChanged {
+		changes = append(changes, cmdshared.OptionString(change.Old.Option)+" -> "+cmdshared.OptionString(change.New.Option))
+	}
+	return changes
+}
+
+func sideString(side string) string {
+	if side == core.EmptySide {
+		return core.UniversalSide
+	}
+	return side
+}
+
+func printDiffText(diff cmdshared.PackDiff) {
+	if len(diff.VersionChanges) > 0 {
+		fmt.Println("Versions:")
+		for _, v := range diff.VersionChanges {
+			fmt.Println("  " + describeVersionChange(v))
+		}
+	}
+	if len(diff.Added) > 0 {
+		fmt.Println("Added:")
+		for _, v := range diff.Added {
+			fmt.Printf("  + %s (%s)\n", v.Name, v.FileName)
+		}
+	}
+	if len(diff.Removed) > 0 {
+		fmt.Println("Removed:")
+		for _, v := range diff.Removed {
+			fmt.Printf("  - %s (%s)\n", v.Name, v.FileName)
+		}
+	}
+	if len(diff.Changed) > 0 {
+		fmt.Println("Changed:")
+		for _, v := range diff.Changed {
+			fmt.Printf("  * %s: %s\n", v.New.Name, strings.Join(describeModChange(v), ", "))
+		}
+	}
+	if len(diff.OverridesAdded)+len(diff.OverridesRemoved)+len(diff.OverridesModified) > 0 {
+		fmt.Println("Other files:")
+		for _, v := range diff.OverridesAdded {
+			fmt.Println("  + " + v)
+		}
+		for _, v := range diff.OverridesRemoved {
+			fmt.Println("  - " + v)
+		}
+		for _, v := range diff.OverridesModified {
+			fmt.Println("  * " + v)
+		}
+	}
+}
+
+func printDiffMarkdown(diff cmdshared.PackDiff) {
+	var sections []string
+	if len(diff.VersionChanges) > 0 {
+		section := "## Versions\n"
+		for _, v := range diff.VersionChanges {
+			section += "- " + describeVersionChange(v) + "\n"
+		}
+		sections = append(sections, section)
+	}
+	if len(diff.Added) > 0 {
+		section := "## Added\n"
+		for _, v := range diff.Added {
+			section += fmt.Sprintf("- %s (`%s`)\n", v.Name, v.FileName)
+		}
+		sections = append(sections, section)
+	}
+	if len(diff.Removed) > 0 {
+		section := "## Removed\n"
+		for _, v := range diff.Removed {
+			section += fmt.Sprintf("- %s (`%s`)\n", v.Name, v.FileName)
+		}
+		sections = append(sections, section)
+	}
+	if len(diff.Changed) > 0 {
+		section := "## Changed\n"
+		for _, v := range diff.Changed {
+			section += fmt.Sprintf("- %s: %s\n", v.New.Name, strings.Join(describeModChange(v), ", "))
+		}
+		sections = append(sections, section)
+	}
+	if len(diff.OverridesAdded)+len(diff.OverridesRemoved)+len(diff.OverridesModified) > 0 {
+		section := "## Other files\n"
+		for _, v := range diff.OverridesAdded {
+			section += "- Added `" + v + "`\n"
+		}
+		for _, v := range diff.OverridesRemoved {
+			section += "- Removed `" + v + "`\n"
+		}
+		for _, v := range diff.OverridesModified {
+			section += "- Modified `" + v + "`\n"
+		}
+		sections = append(sections, section)
+	}
+	fmt.Print(strings.Join(sections, "\n"))
+}
+
+func init() {
+	rootCmd.AddCommand(diffCmd)
+
+	diffCmd.Flags().String("format", "text", "The output format (text or markdown)")
+	_ = viper.BindPFlag("diff.format", diffCmd.Flags().Lookup("format"))
+}
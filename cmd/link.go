@@ -0,0 +1,199 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"slices"
+
+	"github.com/packwiz/packwiz/cmdshared"
+	"github.com/packwiz/packwiz/core"
+	"github.com/spf13/cobra"
+)
+
+// linkCmd represents the link command
+var linkCmd = &cobra.Command{
+	Use:   "link [name]",
+	Short: "Find external files on other mod sites, and add their metadata (or for all files, if no name is given)",
+	Long: `Find external files on other mod sites, and add their metadata (or for all files, if no name is given).
+Files are matched by their hashes, so only exactly the same file is linked. This allows files to be exported as
+references to each site rather than being included in the exported pack (e.g. files added from CurseForge can be
+downloaded from Modrinth when exporting to Modrinth).`,
+	Aliases: []string{"cf-to-mr"},
+	Args:    cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		fmt.Println("Loading modpack...")
+		pack, err := core.LoadPack()
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		index, err := pack.LoadIndex()
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+
+		var mods []*core.Mod
+		if len(args) > 0 {
+			modPath, ok := index.FindMod(args[0])
+			if !ok {
+				fmt.Println("Can't find this file; please ensure you have run packwiz refresh and use the name of the .pw.toml file (defaults to the project slug)")
+				os.Exit(1)
+			}
+			modData, err := core.LoadMod(modPath)
+			if err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+			mods = []*core.Mod{&modData}
+		} else {
+			fmt.Println("Reading metadata files...")
+			mods, err = index.LoadAllMods()
+			if err != nil {
+				fmt.Printf("Failed to read metadata files: %v\n", err)
+				os.Exit(1)
+			}
+		}
+
+		var matcherNames []string
+		for k, v := range core.Updaters {
+			if _, ok := v.(core.FileMatcher); ok {
+				matcherNames = append(matcherNames, k)
+			}
+		}
+		slices.Sort(matcherNames)
+
+		// Find the files that don't have metadata for every update system, and the hashes needed to match them
+		var unlinkedMods []*core.Mod
+		var hashFormats []string
+		for _, modData := range mods {
+			unlinked := false
+			for _, k := range matcherNames {
+				if _, ok := modData.Update[k]; !ok || modData.IsUpdateStale(k) {
+					unlinked = true
+					for _, format := range core.Updaters[k].(core.FileMatcher).GetMatchHashFormats() {
+						if !slices.Contains(hashFormats, format) {
+							hashFormats = append(hashFormats, format)
+						}
+					}
+				}
+			}
+			if unlinked {
+				unlinkedMods = append(unlinkedMods, modData)
+			}
+		}
+		if len(unlinkedMods) == 0 {
+			fmt.Println("All files are already linked!")
+			return
+		}
+
+		fmt.Printf("Retrieving %v external files...\n", len(unlinkedMods))
+		session, err := core.CreateDownloadSession(unlinkedMods, hashFormats)
+		if err != nil {
+			fmt.Printf("Error retrieving external files: %v\n", err)
+			os.Exit(1)
+		}
+		cmdshared.ListManualDownloads(session)
+
+		hashes := make(map[*core.Mod]map[string]string)
+		for dl := range session.StartDownloads() {
+			if dl.Error != nil {
+				fmt.Printf("Download of %s (%s) failed: %v\n", dl.Mod.Name, dl.Mod.FileName, dl.Error)
+				continue
+			}
+			for _, warning := range dl.Warnings {
+				fmt.Printf("Warning for %s (%s): %v\n", dl.Mod.Name, dl.Mod.FileName, warning)
+			}
+			_ = dl.File.Close()
+			hashes[dl.Mod] = dl.Hashes
+		}
+		err = session.SaveIndex()
+		if err != nil {
+			fmt.Printf("Error saving cache index: %v\n", err)
+			os.Exit(1)
+		}
+
+		fmt.Println("Matching files...")
+		linkedMods := make(map[*core.Mod]bool)
+		for _, k := range matcherNames {
+			var matcherMods []*core.Mod
+			var matcherHashes []map[string]string
+			for _, modData := range unlinkedMods {
+				if _, ok := modData.Update[k]; ok && !modData.IsUpdateStale(k) {
+					continue
+				}
+				if h, ok := hashes[modData]; ok {
+					matcherMods = append(matcherMods, modData)
+					matcherHashes = append(matcherHashes, h)
+				}
+			}
+			if len(matcherMods) == 0 {
+				continue
+			}
+
+			matches, err := core.Updaters[k].(core.FileMatcher).MatchFiles(matcherMods, matcherHashes)
+			if err != nil {
+				fmt.Printf("Failed to match files with %s: %v\n", k, err)
+				continue
+			}
+			for i, match := range matches {
+				if match == nil {
+					continue
+				}
+				modData := matcherMods[i]
+				if modData.Update == nil {
+					modData.Update = make(map[string]map[string]interface{})
+				}
+				modData.Update[k] = match.UpdateData
+				// Files that must be downloaded using metadata can be downloaded from a direct URL instead; this
+				// is the same file, so the hash doesn't change
+				if modData.Download.Mode != core.ModeURL && modData.Download.Mode != "" && match.DownloadURL != "" {
+					modData.Download.Mode = core.ModeURL
+					modData.Download.URL = match.DownloadURL
+				}
+				linkedMods[modData] = true
+				fmt.Printf("Linked %s to %s\n", modData.Name, k)
+			}
+		}
+
+		if len(linkedMods) == 0 {
+			fmt.Println("No files could be linked")
+			return
+		}
+		for _, modData := range unlinkedMods {
+			if !linkedMods[modData] {
+				continue
+			}
+			format, hash, err := modData.Write()
+			if err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+			err = index.RefreshFileWithHash(modData.GetFilePath(), format, hash, true)
+			if err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+		}
+		err = index.Write()
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		err = pack.UpdateIndexHash()
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		err = pack.Write()
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		fmt.Printf("%d file(s) linked successfully!\n", len(linkedMods))
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(linkCmd)
+}
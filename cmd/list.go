@@ -1,11 +1,14 @@
 package cmd
 
 import (
+	"encoding/csv"
+	"encoding/json"
 	"fmt"
 	"os"
 	"sort"
 	"strings"
 
+	"github.com/packwiz/packwiz/cmdshared"
 	"github.com/packwiz/packwiz/core"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
@@ -17,6 +20,11 @@ var listCmd = &cobra.Command{
 	Short: "List all the mods in the modpack",
 	Args:  cobra.NoArgs,
 	Run: func(cmd *cobra.Command, args []string) {
+		format := viper.GetString("list.format")
+		if format != "text" && format != "json" && format != "csv" && format != "markdown" {
+			fmt.Printf("Unknown output format %s (must be text, json, csv or markdown)\n", format)
+			os.Exit(1)
+		}
 
 		// Load pack
 		pack, err := core.LoadPack()
@@ -57,12 +65,76 @@ var listCmd = &cobra.Command{
 			mods = mods[:i]
 		}
 
+		// Filter mods by update source
+		if viper.IsSet("list.source") {
+			source := viper.GetString("list.source")
+			if _, ok := core.Updaters[source]; !ok {
+				fmt.Printf("Invalid source %q, must be the name of an update system (e.g. modrinth or curseforge)\n", source)
+				os.Exit(1)
+			}
+
+			i := 0
+			for _, mod := range mods {
+				if _, ok := mod.Update[source]; ok && !mod.IsUpdateStale(source) {
+					mods[i] = mod
+					i++
+				}
+			}
+			mods = mods[:i]
+		}
+
+		// Filter mods by pin state
+		if viper.GetBool("list.pinned") {
+			i := 0
+			for _, mod := range mods {
+				if mod.Pin {
+					mods[i] = mod
+					i++
+				}
+			}
+			mods = mods[:i]
+		}
+
+		// Filter optional mods
+		if viper.GetBool("list.optional") {
+			i := 0
+			for _, mod := range mods {
+				if mod.Option != nil && mod.Option.Optional {
+					mods[i] = mod
+					i++
+				}
+			}
+			mods = mods[:i]
+		}
+
 		sort.Slice(mods, func(i, j int) bool {
 			return strings.ToLower(mods[i].Name) < strings.ToLower(mods[j].Name)
 		})
 
 		// Print mods
-		if viper.GetBool("list.version") {
+		if format != "text" {
+			entries := make([]listEntry, len(mods))
+			for i, mod := range mods {
+				entries[i] = getListEntry(index, mod)
+			}
+			switch format {
+			case "json":
+				data, err := json.MarshalIndent(entries, "", "\t")
+				if err != nil {
+					fmt.Println(err)
+					os.Exit(1)
+				}
+				fmt.Println(string(data))
+			case "csv":
+				err = printListCSV(entries)
+				if err != nil {
+					fmt.Println(err)
+					os.Exit(1)
+				}
+			case "markdown":
+				printListMarkdown(entries)
+			}
+		} else if viper.GetBool("list.version") {
 			for _, mod := range mods {
 				fmt.Printf("%s (%s)\n", mod.Name, mod.FileName)
 			}
@@ -74,6 +146,126 @@ var listCmd = &cobra.Command{
 	},
 }
 
+type listEntry struct {
+	Name         string                 `json:"name"`
+	FileName     string                 `json:"filename"`
+	MetaFile     string                 `json:"metafile"`
+	Side         string                 `json:"side"`
+	Pinned       bool                   `json:"pinned"`
+	Option       *listOption            `json:"option,omitempty"`
+	DownloadMode string                 `json:"download-mode"`
+	HashFormat   string                 `json:"hash-format"`
+	Hash         string                 `json:"hash"`
+	Updaters     map[string]listUpdater `json:"updaters"`
+}
+
+type listOption struct {
+	Optional    bool   `json:"optional"`
+	Default     bool   `json:"default"`
+	Description string `json:"description,omitempty"`
+}
+
+type listUpdater struct {
+	ProjectID string `json:"project-id,omitempty"`
+	VersionID string `json:"version-id,omitempty"`
+	Stale     bool   `json:"stale,omitempty"`
+}
+
+func getListEntry(index core.Index, mod *core.Mod) listEntry {
+	entry := listEntry{
+		Name:         mod.Name,
+		FileName:     mod.FileName,
+		Side:         sideString(mod.Side),
+		Pinned:       mod.Pin,
+		DownloadMode: mod.Download.Mode,
+		HashFormat:   mod.Download.HashFormat,
+		Hash:         mod.Download.Hash,
+		Updaters:     make(map[string]listUpdater),
+	}
+	entry.MetaFile, _ = index.RelIndexPath(mod.GetFilePath())
+	if mod.Option != nil {
+		entry.Option = &listOption{
+			Optional:    mod.Option.Optional,
+			Default:     mod.Option.Default,
+			Description: mod.Option.Description,
+		}
+	}
+	if entry.DownloadMode == "" {
+		entry.DownloadMode = core.ModeURL
+	}
+	for k := range mod.Update {
+		updater := listUpdater{Stale: mod.IsUpdateStale(k)}
+		if data, ok := mod.GetParsedUpdateData(k); ok {
+			if id, ok := data.(core.IdentifiableUpdateData); ok {
+				updater.ProjectID = id.GetProjectID()
+				updater.VersionID = id.GetVersionID()
+			}
+		}
+		entry.Updaters[k] = updater
+	}
+	return entry
+}
+
+// updaterString formats the updaters of a file as a list of updater:project-id:version-id, sorted by updater name
+func (e listEntry) updaterString() string {
+	names := make([]string, 0, len(e.Updaters))
+	for k := range e.Updaters {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	for i, k := range names {
+		if e.Updaters[k].ProjectID != "" {
+			names[i] = k + ":" + e.Updaters[k].ProjectID + ":" + e.Updaters[k].VersionID
+		}
+	}
+	return strings.Join(names, " ")
+}
+
+func (e listEntry) optionString() string {
+	if e.Option == nil {
+		return cmdshared.OptionString(nil)
+	}
+	return cmdshared.OptionString(&core.ModOption{Optional: e.Option.Optional, Default: e.Option.Default})
+}
+
+func printListCSV(entries []listEntry) error {
+	w := csv.NewWriter(os.Stdout)
+	err := w.Write([]string{"name", "filename", "metafile", "side", "pinned", "optional", "default", "description",
+		"download-mode", "hash-format", "hash", "updaters"})
+	if err != nil {
+		return err
+	}
+	for _, v := range entries {
+		optional, optionDefault, description := "false", "false", ""
+		if v.Option != nil {
+			optional = fmt.Sprint(v.Option.Optional)
+			optionDefault = fmt.Sprint(v.Option.Default)
+			description = v.Option.Description
+		}
+		err = w.Write([]string{v.Name, v.FileName, v.MetaFile, v.Side, fmt.Sprint(v.Pinned), optional, optionDefault,
+			description, v.DownloadMode, v.HashFormat, v.Hash, v.updaterString()})
+		if err != nil {
+			return err
+		}
+	}
+	w.Flush()
+	return w.Error()
+}
+
+func printListMarkdown(entries []listEntry) {
+	escape := strings.NewReplacer("|", "\\|", "\n", " ")
+	fmt.Println("| Name | File | Side | Pinned | Option | Source |")
+	fmt.Println("| --- | --- | --- | --- | --- | --- |")
+	for _, v := range entries {
+		pinned := ""
+		if v.Pinned {
+			pinned = "yes"
+		}
+		fmt.Printf("| %s | %s | %s | %s | %s | %s |\n", escape.Replace(v.Name), escape.Replace(v.FileName), v.Side,
+			pinned, v.optionString(), escape.Replace(v.updaterString()))
+	}
+}
+
 func init() {
 	rootCmd.AddCommand(listCmd)
 
@@ -81,5 +273,12 @@ func init() {
 	_ = viper.BindPFlag("list.version", listCmd.Flags().Lookup("version"))
 	listCmd.Flags().StringP("side", "s", "", "Filter mods by side (e.g., client or server)")
 	_ = viper.BindPFlag("list.side", listCmd.Flags().Lookup("side"))
-
+	listCmd.Flags().String("source", "", "Filter mods by update source (e.g., modrinth or curseforge)")
+	_ = viper.BindPFlag("list.source", listCmd.Flags().Lookup("source"))
+	listCmd.Flags().Bool("pinned", false, "Only list pinned mods")
+	_ = viper.BindPFlag("list.pinned", listCmd.Flags().Lookup("pinned"))
+	listCmd.Flags().Bool("optional", false, "Only list optional mods")
+	_ = viper.BindPFlag("list.optional", listCmd.Flags().Lookup("optional"))
+	listCmd.Flags().String("format", "text", "The output format (text, json, csv or markdown)")
+	_ = viper.BindPFlag("list.format", listCmd.Flags().Lookup("format"))
 }
@@ -4,8 +4,10 @@ import (
 	"fmt"
 	"os"
 
+	"github.com/packwiz/packwiz/cmdshared"
 	"github.com/packwiz/packwiz/core"
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 )
 
 // removeCmd represents the remove command
@@ -31,16 +33,37 @@ var removeCmd = &cobra.Command{
 			fmt.Println("Can't find this file; please ensure you have run packwiz refresh and use the name of the .pw.toml file (defaults to the project slug)")
 			os.Exit(1)
 		}
-		err = os.Remove(resolvedMod)
-		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+
+		toRemove := []string{resolvedMod}
+		if viper.GetBool("offline") {
+			// Dependency information is retrieved from mod sites
+			fmt.Println("Skipping dependency check in offline mode")
+			if viper.GetBool("remove.cascade") {
+				fmt.Println("Warning: unused dependencies can't be found in offline mode, so none will be removed")
+			}
+		} else {
+			mods, err := index.LoadAllMods()
+			if err != nil {
+				fmt.Printf("Failed to check dependencies: %v\n", err)
+			} else {
+				toRemove = checkDependents(resolvedMod, mods, pack)
+			}
+		}
+
+		for _, v := range toRemove {
+			err = os.Remove(v)
+			if err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
 		}
 		fmt.Println("Removing file from index...")
-		err = index.RemoveFile(resolvedMod)
-		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+		for _, v := range toRemove {
+			err = index.RemoveFile(v)
+			if err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
 		}
 		err = index.Write()
 		if err != nil {
@@ -62,6 +85,137 @@ var removeCmd = &cobra.Command{
 	},
 }
 
+// checkDependents warns if other files require the file being removed (asking whether to continue), and finds
+// dependencies that are no longer needed if --cascade is used; returning the paths of all the files to remove.
+// Only the project IDs stored in metadata files are used to find dependencies, so files aren't downloaded to be
+// matched with other sites, and dependency information is only retrieved for files that could require the files being
+// removed.
+func checkDependents(modPath string, mods []*core.Mod, pack core.Pack) []string {
+	toRemove := []string{modPath}
+	var target *core.Mod
+	for _, v := range mods {
+		if v.GetFilePath() == modPath {
+			target = v
+			break
+		}
+	}
+	if target == nil {
+		return toRemove
+	}
+
+	installed := cmdshared.GetInstalledProjects(mods)
+	deps := make(map[*core.Mod]cmdshared.ModDependencies)
+	fetched := make(map[*core.Mod]bool)
+	// fetchDeps retrieves the dependency information of the given files, if it hasn't already been retrieved
+	fetchDeps := func(files []*core.Mod) {
+		var toFetch []*core.Mod
+		for _, v := range files {
+			if !fetched[v] {
+				fetched[v] = true
+				toFetch = append(toFetch, v)
+			}
+		}
+		if len(toFetch) == 0 {
+			return
+		}
+		results, errs := cmdshared.GetDependencyInfo(toFetch, pack)
+		for _, err := range errs {
+			fmt.Printf("Warning: %v\n", err)
+		}
+		for k, v := range results {
+			deps[k] = v
+		}
+	}
+
+	// requiredBy finds the files (that aren't being removed) requiring the given file
+	removing := map[*core.Mod]bool{target: true}
+	requiredBy := func(modData *core.Mod) []*core.Mod {
+		projectIDs := cmdshared.GetProjectIDs(modData)
+		// Only files whose dependencies are retrieved from a site the file has a project ID for can require it
+		var candidates []*core.Mod
+		for _, other := range mods {
+			if removing[other] {
+				continue
+			}
+			if k, ok := cmdshared.GetDependencyUpdater(other); ok && projectIDs[k] != "" {
+				candidates = append(candidates, other)
+			}
+		}
+		fetchDeps(candidates)
+
+		var dependents []*core.Mod
+		for _, other := range candidates {
+			dep, ok := deps[other]
+			if !ok {
+				continue
+			}
+			for _, v := range dep.Info.Required {
+				if v.ProjectID == projectIDs[dep.Updater] {
+					dependents = append(dependents, other)
+					break
+				}
+			}
+		}
+		return dependents
+	}
+
+	dependents := requiredBy(target)
+	if len(dependents) > 0 {
+		fmt.Printf("Warning: %s is required by:\n", target.Name)
+		for _, v := range dependents {
+			fmt.Println(v.Name)
+		}
+		if viper.GetBool("non-interactive") {
+			// Don't break the pack without being asked to
+			if !viper.GetBool("remove.force") && !viper.GetBool("remove.cascade") {
+				fmt.Println("Use --force to remove it anyway")
+				os.Exit(1)
+			}
+		} else if !viper.GetBool("remove.force") && !cmdshared.PromptYesNoDefaultNo("Would you like to remove it anyway? [y/N]: ") {
+			fmt.Println("Cancelled!")
+			os.Exit(1)
+		}
+	}
+
+	if !viper.GetBool("remove.cascade") {
+		return toRemove
+	}
+	// Remove dependencies that were added automatically, and are no longer required by any other file
+	pending := []*core.Mod{target}
+	var cascaded []*core.Mod
+	for len(pending) > 0 {
+		current := pending[0]
+		pending = pending[1:]
+		fetchDeps([]*core.Mod{current})
+		dep, ok := deps[current]
+		if !ok {
+			continue
+		}
+		for _, v := range dep.Info.Required {
+			depMod, ok := installed.Find(dep.Updater, v.ProjectID)
+			if !ok || !depMod.Dependency || removing[depMod] {
+				continue
+			}
+			if len(requiredBy(depMod)) > 0 {
+				continue
+			}
+			removing[depMod] = true
+			cascaded = append(cascaded, depMod)
+			pending = append(pending, depMod)
+		}
+	}
+	for _, v := range cascaded {
+		fmt.Printf("Removing unused dependency %s\n", v.Name)
+		toRemove = append(toRemove, v.GetFilePath())
+	}
+	return toRemove
+}
+
 func init() {
 	rootCmd.AddCommand(removeCmd)
+
+	removeCmd.Flags().Bool("cascade", false, "Also remove dependencies that were added automatically and are no longer required by any other file")
+	_ = viper.BindPFlag("remove.cascade", removeCmd.Flags().Lookup("cascade"))
+	removeCmd.Flags().Bool("force", false, "Remove the file without asking, even if other files require it")
+	_ = viper.BindPFlag("remove.force", removeCmd.Flags().Lookup("force"))
 }
@@ -0,0 +1,115 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/packwiz/packwiz/core"
+	"github.com/spf13/viper"
+)
+
+// testDepUpdater is an update system with dependency information, recording the files it was asked about
+type testDepUpdater struct {
+	testCheckUpdater
+	required map[string][]string
+	queried  *[]string
+}
+
+type testProjectData string
+
+func (d testProjectData) GetProjectID() string { return string(d) }
+func (d testProjectData) GetVersionID() string { return "" }
+
+func (testDepUpdater) ParseUpdate(data map[string]interface{}) (interface{}, error) {
+	project, _ := data["project"].(string)
+	return testProjectData(project), nil
+}
+
+func (u testDepUpdater) GetDependencyInfo(mods []*core.Mod, _ core.Pack) ([]core.DependencyInfo, error) {
+	infos := make([]core.DependencyInfo, len(mods))
+	for i, v := range mods {
+		*u.queried = append(*u.queried, v.Name)
+		for _, dep := range u.required[v.Name] {
+			infos[i].Required = append(infos[i].Required, core.Dependency{ProjectID: dep})
+		}
+	}
+	return infos, nil
+}
+
+func writeTestMod(t *testing.T, dir string, name string, updater string, dependency bool) *core.Mod {
+	t.Helper()
+	metaFile := filepath.Join(dir, name+core.MetaExtension)
+	dep := "false"
+	if dependency {
+		dep = "true"
+	}
+	err := os.WriteFile(metaFile, []byte(`name = "`+name+`"
+filename = "`+name+`.jar"
+dependency = `+dep+`
+[download]
+url = "https://example.com/`+name+`.jar"
+hash-format = "sha1"
+hash = "abc"
+[update.`+updater+`]
+project = "`+name+`"
+`), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mod, err := core.LoadMod(metaFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &mod
+}
+
+func TestCheckDependents(t *testing.T) {
+	var queried []string
+	core.Updaters["test-deps"] = testDepUpdater{
+		required: map[string][]string{
+			"app":    {"lib", "shared"},
+			"other":  {"shared"},
+			"lib":    {"sublib"},
+			"sublib": nil,
+		},
+		queried: &queried,
+	}
+	// Files from another site can't require files that don't have a project ID for it
+	core.Updaters["test-other"] = testDepUpdater{required: map[string][]string{"unrelated": {"app"}}, queried: &queried}
+	t.Cleanup(func() {
+		delete(core.Updaters, "test-deps")
+		delete(core.Updaters, "test-other")
+		viper.Set("non-interactive", nil)
+		viper.Set("remove.cascade", nil)
+	})
+	viper.Set("non-interactive", true)
+
+	dir := t.TempDir()
+	mods := []*core.Mod{
+		writeTestMod(t, dir, "app", "test-deps", false),
+		writeTestMod(t, dir, "other", "test-deps", false),
+		writeTestMod(t, dir, "lib", "test-deps", true),
+		writeTestMod(t, dir, "sublib", "test-deps", true),
+		writeTestMod(t, dir, "shared", "test-deps", true),
+		writeTestMod(t, dir, "unrelated", "test-other", false),
+	}
+
+	toRemove := checkDependents(mods[0].GetFilePath(), mods, core.Pack{})
+	if !slices.Equal(toRemove, []string{mods[0].GetFilePath()}) {
+		t.Errorf("Expected only the file to be removed without --cascade, got %v", toRemove)
+	}
+
+	viper.Set("remove.cascade", true)
+	queried = nil
+	toRemove = checkDependents(mods[0].GetFilePath(), mods, core.Pack{})
+	// shared is still required by other
+	want := []string{mods[0].GetFilePath(), mods[2].GetFilePath(), mods[3].GetFilePath()}
+	if !slices.Equal(toRemove, want) {
+		t.Errorf("Expected %v to be removed, got %v", want, toRemove)
+	}
+	if slices.Contains(queried, "unrelated") {
+		t.Errorf("Expected dependency information not to be retrieved for files from other sites, got %v", queried)
+	}
+}
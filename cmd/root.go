@@ -64,6 +64,18 @@ func init() {
 	rootCmd.PersistentFlags().String("cache", defaultCacheDir, "The directory where packwiz will cache downloaded mods")
 	_ = viper.BindPFlag("cache.directory", rootCmd.PersistentFlags().Lookup("cache"))
 
+	rootCmd.PersistentFlags().Int("download-threads", core.DefaultDownloadThreads, "The number of files to download at the same time when exporting or rehashing")
+	_ = viper.BindPFlag("download-threads", rootCmd.PersistentFlags().Lookup("download-threads"))
+
+	rootCmd.PersistentFlags().Int("download-retries", core.DefaultDownloadRetries, "The number of times to retry a failed download from each URL, before trying the next mirror")
+	_ = viper.BindPFlag("download-retries", rootCmd.PersistentFlags().Lookup("download-retries"))
+
+	rootCmd.PersistentFlags().Duration("download-timeout", core.DefaultDownloadTimeout, "How long a download can go without receiving data before it is retried (0 to never time out)")
+	_ = viper.BindPFlag("download-timeout", rootCmd.PersistentFlags().Lookup("download-timeout"))
+
+	rootCmd.PersistentFlags().Bool("offline", false, "Only use files from the download cache, without downloading files or retrieving metadata from mod sites when exporting or rehashing")
+	_ = viper.BindPFlag("offline", rootCmd.PersistentFlags().Lookup("offline"))
+
 	file, err := core.GetPackwizLocalStore()
 	if err != nil {
 		fmt.Println(err)
@@ -48,6 +48,11 @@ var serveCmd = &cobra.Command{
 				fmt.Println(err)
 				os.Exit(1)
 			}
+			localFiles, err := getLocalFiles(index)
+			if err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
 			packServeDir := filepath.Dir(viper.GetString("pack-file"))
 			packFileName := filepath.Base(viper.GetString("pack-file"))
 
@@ -101,6 +106,11 @@ var serveCmd = &cobra.Command{
 							fmt.Println("Failed to refresh pack", err)
 							return
 						}
+						localFiles, err = getLocalFiles(index)
+						if err != nil {
+							fmt.Println("Failed to load local files", err)
+							return
+						}
 
 						// Downgrade to a read lock
 						refreshMutex.Unlock()
@@ -108,8 +118,9 @@ var serveCmd = &cobra.Command{
 					refreshMutex.RLock()
 				} else {
 					refreshMutex.RLock()
-					// Only allow indexed files
-					if _, found := index.Files[indexRelPath]; !found {
+					// Only allow indexed files, and files referenced by metadata files in the local store
+					absPath, _ := filepath.Abs(destPath)
+					if _, found := index.Files[indexRelPath]; !found && !localFiles[absPath] {
 						fmt.Printf("File not found: %s\n", destPath)
 						refreshMutex.RUnlock()
 						w.WriteHeader(404)
@@ -149,6 +160,32 @@ var serveCmd = &cobra.Command{
 	},
 }
 
+// getLocalFiles returns the absolute paths of the files in the local store that are referenced by metadata files
+func getLocalFiles(index core.Index) (map[string]bool, error) {
+	mods, err := index.LoadAllMods()
+	if err != nil {
+		return nil, err
+	}
+	localFiles := make(map[string]bool)
+	for _, mod := range mods {
+		if !core.IsLocalURL(mod.Download.URL) {
+			continue
+		}
+		path, err := mod.GetLocalFilePath()
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve local file of %s: %w", mod.Name, err)
+		}
+		absPath, err := filepath.Abs(path)
+		if err != nil {
+			return nil, err
+		}
+		if core.IsInLocalStore(absPath) {
+			localFiles[absPath] = true
+		}
+	}
+	return localFiles, nil
+}
+
 func doServeRefresh(pack *core.Pack, index *core.Index) error {
 	var err error
 	*pack, err = core.LoadPack()
@@ -1,8 +1,12 @@
 package cmd
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
+	"sort"
+	"strings"
 
 	"github.com/packwiz/packwiz/cmdshared"
 	"github.com/packwiz/packwiz/core"
@@ -17,8 +21,11 @@ var UpdateCmd = &cobra.Command{
 	Aliases: []string{"upgrade"},
 	Args:    cobra.MaximumNArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
-		// TODO: --check flag?
 		// TODO: specify multiple files to update at once?
+		if viper.GetBool("update.check") {
+			checkUpdates(args)
+			return
+		}
 
 		fmt.Println("Loading modpack...")
 		pack, err := core.LoadPack()
@@ -42,22 +49,12 @@ var UpdateCmd = &cobra.Command{
 				os.Exit(1)
 			}
 			for _, modData := range mods {
-				updaterFound := false
-				for k := range modData.Update {
-					slice, ok := filesWithUpdater[k]
-					if !ok {
-						_, ok = core.Updaters[k]
-						if !ok {
-							continue
-						}
-						slice = []*core.Mod{}
-					}
-					updaterFound = true
-					filesWithUpdater[k] = append(slice, modData)
-				}
-				if !updaterFound {
+				updaterName, ok := cmdshared.GetPrimaryUpdater(modData)
+				if !ok {
 					fmt.Printf("A supported update system for \"%s\" cannot be found.\n", modData.Name)
+					continue
 				}
+				filesWithUpdater[updaterName] = append(filesWithUpdater[updaterName], modData)
 			}
 
 			fmt.Println("Checking for updates...")
@@ -111,7 +108,9 @@ var UpdateCmd = &cobra.Command{
 					fmt.Println(err.Error())
 					continue
 				}
+				stale := cmdshared.RelinkUpdatedFiles(v, k)
 				for _, modData := range v {
+					printStaleUpdaters(modData, stale[modData])
 					format, hash, err := modData.Write()
 					if err != nil {
 						fmt.Println(err.Error())
@@ -144,54 +143,47 @@ var UpdateCmd = &cobra.Command{
 				os.Exit(1)
 			}
 			singleUpdatedName = modData.Name
-			updaterFound := false
-			for k := range modData.Update {
-				updater, ok := core.Updaters[k]
-				if !ok {
-					continue
-				}
-				updaterFound = true
+			updaterName, ok := cmdshared.GetPrimaryUpdater(&modData)
+			if !ok {
+				// TODO: use file name instead of Name when len(Name) == 0 in all places?
+				fmt.Println("A supported update system for \"" + modData.Name + "\" cannot be found.")
+				os.Exit(1)
+			}
+			updater := core.Updaters[updaterName]
+
+			check, err := updater.CheckUpdate([]*core.Mod{&modData}, pack)
+			if err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+			if len(check) != 1 {
+				fmt.Println("Invalid update check response")
+				os.Exit(1)
+			}
 
-				check, err := updater.CheckUpdate([]*core.Mod{&modData}, pack)
+			if check[0].UpdateAvailable {
+				fmt.Printf("Update available: %s\n", check[0].UpdateString)
+
+				err = updater.DoUpdate([]*core.Mod{&modData}, []interface{}{check[0].CachedState})
 				if err != nil {
 					fmt.Println(err)
 					os.Exit(1)
 				}
-				if len(check) != 1 {
-					fmt.Println("Invalid update check response")
+				printStaleUpdaters(&modData, cmdshared.RelinkUpdatedFiles([]*core.Mod{&modData}, updaterName)[&modData])
+
+				format, hash, err := modData.Write()
+				if err != nil {
+					fmt.Println(err)
 					os.Exit(1)
 				}
-
-				if check[0].UpdateAvailable {
-					fmt.Printf("Update available: %s\n", check[0].UpdateString)
-
-					err = updater.DoUpdate([]*core.Mod{&modData}, []interface{}{check[0].CachedState})
-					if err != nil {
-						fmt.Println(err)
-						os.Exit(1)
-					}
-
-					format, hash, err := modData.Write()
-					if err != nil {
-						fmt.Println(err)
-						os.Exit(1)
-					}
-					err = index.RefreshFileWithHash(modPath, format, hash, true)
-					if err != nil {
-						fmt.Println(err)
-						os.Exit(1)
-					}
-				} else {
-					fmt.Printf("\"%s\" is already up to date!\n", modData.Name)
-					return
+				err = index.RefreshFileWithHash(modPath, format, hash, true)
+				if err != nil {
+					fmt.Println(err)
+					os.Exit(1)
 				}
-
-				break
-			}
-			if !updaterFound {
-				// TODO: use file name instead of Name when len(Name) == 0 in all places?
-				fmt.Println("A supported update system for \"" + modData.Name + "\" cannot be found.")
-				os.Exit(1)
+			} else {
+				fmt.Printf("\"%s\" is already up to date!\n", modData.Name)
+				return
 			}
 		}
 
@@ -218,9 +210,178 @@ var UpdateCmd = &cobra.Command{
 	},
 }
 
+func printStaleUpdaters(modData *core.Mod, stale []string) {
+	if len(stale) > 0 {
+		fmt.Printf("Couldn't find the updated %s on %s; run packwiz link to find it once it is available\n",
+			modData.Name, strings.Join(stale, ", "))
+	}
+}
+
+type updateCheckResult struct {
+	Name            string `json:"name"`
+	MetaFile        string `json:"metafile"`
+	Updater         string `json:"updater"`
+	UpdateAvailable bool   `json:"update-available"`
+	Pinned          bool   `json:"pinned"`
+	OldFile         string `json:"old-file"`
+	NewFile         string `json:"new-file,omitempty"`
+	UpdateString    string `json:"update-string,omitempty"`
+	Error           string `json:"error,omitempty"`
+}
+
+// Exit codes of update --check
+const (
+	checkExitUpdates = 1
+	// checkExitError is used when updates could not be checked, and no updates were found for other files
+	checkExitError = 2
+	// checkExitUpdatesAndErrors is used when updates were found for some files, but could not be checked for others
+	checkExitUpdatesAndErrors = 3
+)
+
+// checkUpdates prints the available updates for the given file (or all files) without updating them, and exits with
+// the code given by getCheckExitCode
+func checkUpdates(args []string) {
+	format := viper.GetString("update.format")
+	if format != "text" && format != "json" {
+		fmt.Printf("Unknown output format %s (must be text or json)\n", format)
+		os.Exit(checkExitError)
+	}
+
+	pack, err := core.LoadPack()
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(checkExitError)
+	}
+	index, err := pack.LoadIndex()
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(checkExitError)
+	}
+
+	var mods []*core.Mod
+	if viper.GetBool("update.all") || len(args) < 1 || len(args[0]) == 0 {
+		mods, err = index.LoadAllMods()
+		if err != nil {
+			fmt.Printf("Failed to read metadata files: %v\n", err)
+			os.Exit(checkExitError)
+		}
+	} else {
+		modPath, ok := index.FindMod(args[0])
+		if !ok {
+			fmt.Println("Can't find this file; please ensure you have run packwiz refresh and use the name of the .pw.toml file (defaults to the project slug)")
+			os.Exit(checkExitError)
+		}
+		modData, err := core.LoadMod(modPath)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(checkExitError)
+		}
+		mods = []*core.Mod{&modData}
+	}
+
+	results := getUpdateCheckResults(mods, pack, index)
+	exitCode := getCheckExitCode(results)
+
+	if format == "json" {
+		data, err := json.MarshalIndent(results, "", "\t")
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(checkExitError)
+		}
+		fmt.Println(string(data))
+	} else {
+		for _, v := range results {
+			if v.Error != "" {
+				fmt.Printf("Failed to check updates for %s: %s\n", v.Name, v.Error)
+			} else if v.UpdateAvailable {
+				if v.Pinned {
+					fmt.Printf("%s: %s (pinned)\n", v.Name, v.UpdateString)
+				} else {
+					fmt.Printf("%s: %s\n", v.Name, v.UpdateString)
+				}
+			}
+		}
+		if exitCode == 0 {
+			fmt.Println("All files are up to date!")
+		}
+	}
+	os.Exit(exitCode)
+}
+
+// getUpdateCheckResults checks for updates of the given files using their primary update systems, sorted by metadata
+// file
+func getUpdateCheckResults(mods []*core.Mod, pack core.Pack, index core.Index) []updateCheckResult {
+	filesWithUpdater := make(map[string][]*core.Mod)
+	for _, modData := range mods {
+		if k, ok := cmdshared.GetPrimaryUpdater(modData); ok {
+			filesWithUpdater[k] = append(filesWithUpdater[k], modData)
+		}
+	}
+
+	results := make([]updateCheckResult, 0)
+	for k, v := range filesWithUpdater {
+		checks, err := core.Updaters[k].CheckUpdate(v, pack)
+		if err == nil && len(checks) != len(v) {
+			err = errors.New("invalid update check response")
+		}
+		for i, modData := range v {
+			result := updateCheckResult{
+				Name:    modData.Name,
+				Updater: k,
+				Pinned:  modData.Pin,
+				OldFile: modData.FileName,
+			}
+			result.MetaFile, _ = index.RelIndexPath(modData.GetFilePath())
+			if err != nil {
+				result.Error = err.Error()
+			} else if checks[i].Error != nil {
+				result.Error = checks[i].Error.Error()
+			} else if checks[i].UpdateAvailable {
+				result.UpdateAvailable = true
+				result.NewFile = checks[i].NewFileName
+				result.UpdateString = checks[i].UpdateString
+			}
+			results = append(results, result)
+		}
+	}
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].MetaFile == results[j].MetaFile {
+			return results[i].Updater < results[j].Updater
+		}
+		return results[i].MetaFile < results[j].MetaFile
+	})
+	return results
+}
+
+// getCheckExitCode returns 0 if all files are up to date, checkExitUpdates if updates are available (ignoring pinned
+// files), checkExitError if updates could not be checked, or checkExitUpdatesAndErrors if both happened
+func getCheckExitCode(results []updateCheckResult) int {
+	updatesFound := false
+	errorsFound := false
+	for _, v := range results {
+		if v.Error != "" {
+			errorsFound = true
+		} else if v.UpdateAvailable && !v.Pinned {
+			updatesFound = true
+		}
+	}
+	if updatesFound && errorsFound {
+		return checkExitUpdatesAndErrors
+	} else if updatesFound {
+		return checkExitUpdates
+	} else if errorsFound {
+		return checkExitError
+	}
+	return 0
+}
+
 func init() {
 	rootCmd.AddCommand(UpdateCmd)
 
 	UpdateCmd.Flags().BoolP("all", "a", false, "Update all external files")
 	_ = viper.BindPFlag("update.all", UpdateCmd.Flags().Lookup("all"))
+	UpdateCmd.Flags().Bool("check", false, "Only check for updates (of the given file, or all files) without applying them; exits with code 1 if updates are available, 2 if updates could not be checked, or 3 if both")
+	_ = viper.BindPFlag("update.check", UpdateCmd.Flags().Lookup("check"))
+	UpdateCmd.Flags().String("format", "text", "The output format of --check (text or json)")
+	_ = viper.BindPFlag("update.format", UpdateCmd.Flags().Lookup("format"))
 }
@@ -0,0 +1,131 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/packwiz/packwiz/core"
+)
+
+type testCheckUpdater struct{}
+
+func (testCheckUpdater) ParseUpdate(data map[string]interface{}) (interface{}, error) {
+	return data, nil
+}
+
+func (testCheckUpdater) CheckUpdate(mods []*core.Mod, _ core.Pack) ([]core.UpdateCheck, error) {
+	checks := make([]core.UpdateCheck, len(mods))
+	for i, v := range mods {
+		switch v.Name {
+		case "Outdated", "Pinned":
+			checks[i] = core.UpdateCheck{UpdateAvailable: true, NewFileName: "new.jar", UpdateString: "old.jar -> new.jar"}
+		case "Broken":
+			checks[i] = core.UpdateCheck{Error: errors.New("project not found")}
+		}
+	}
+	return checks, nil
+}
+
+func (testCheckUpdater) DoUpdate([]*core.Mod, []interface{}) error {
+	return nil
+}
+
+func TestUpdateCheckResults(t *testing.T) {
+	core.Updaters["test-check"] = testCheckUpdater{}
+	t.Cleanup(func() {
+		delete(core.Updaters, "test-check")
+	})
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "index.toml"), []byte(`hash-format = "sha256"`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	index, err := core.LoadIndex(filepath.Join(dir, "index.toml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var mods []*core.Mod
+	for _, name := range []string{"Pinned", "Broken", "Current", "Outdated"} {
+		metaFile := filepath.Join(dir, "mods", name+core.MetaExtension)
+		if err := os.MkdirAll(filepath.Dir(metaFile), 0755); err != nil {
+			t.Fatal(err)
+		}
+		pin := "false"
+		if name == "Pinned" {
+			pin = "true"
+		}
+		err := os.WriteFile(metaFile, []byte(`name = "`+name+`"
+filename = "old.jar"
+pin = `+pin+`
+[download]
+url = "https://example.com/old.jar"
+hash-format = "sha1"
+hash = "abc"
+[update.test-check]
+project = "`+name+`"
+`), 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+		mod, err := core.LoadMod(metaFile)
+		if err != nil {
+			t.Fatal(err)
+		}
+		mods = append(mods, &mod)
+	}
+
+	results := getUpdateCheckResults(mods, core.Pack{}, index)
+	data, err := json.Marshal(results)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded []map[string]interface{}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	// Results are sorted by metadata file, and fields without a value are omitted
+	expected := []map[string]interface{}{{
+		"name": "Broken", "metafile": "mods/Broken.pw.toml", "updater": "test-check", "update-available": false,
+		"pinned": false, "old-file": "old.jar", "error": "project not found",
+	}, {
+		"name": "Current", "metafile": "mods/Current.pw.toml", "updater": "test-check", "update-available": false,
+		"pinned": false, "old-file": "old.jar",
+	}, {
+		"name": "Outdated", "metafile": "mods/Outdated.pw.toml", "updater": "test-check", "update-available": true,
+		"pinned": false, "old-file": "old.jar", "new-file": "new.jar", "update-string": "old.jar -> new.jar",
+	}, {
+		"name": "Pinned", "metafile": "mods/Pinned.pw.toml", "updater": "test-check", "update-available": true,
+		"pinned": true, "old-file": "old.jar", "new-file": "new.jar", "update-string": "old.jar -> new.jar",
+	}}
+	if !reflect.DeepEqual(decoded, expected) {
+		t.Errorf("Unexpected JSON results:\n%s", data)
+	}
+
+	tests := []struct {
+		names []string
+		want  int
+	}{
+		{[]string{"Current"}, 0},
+		{[]string{"Current", "Pinned"}, 0},
+		{[]string{"Outdated", "Pinned"}, checkExitUpdates},
+		{[]string{"Broken", "Pinned"}, checkExitError},
+		{[]string{"Broken", "Outdated"}, checkExitUpdatesAndErrors},
+	}
+	for _, tt := range tests {
+		var subset []updateCheckResult
+		for _, v := range results {
+			for _, name := range tt.names {
+				if v.Name == name {
+					subset = append(subset, v)
+				}
+			}
+		}
+		if got := getCheckExitCode(subset); got != tt.want {
+			t.Errorf("getCheckExitCode(%v) = %d, want %d", tt.names, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,172 @@
+package cmdshared
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/packwiz/packwiz/core"
+	"github.com/spf13/viper"
+)
+
+// InstalledProjects maps update system names to the project IDs installed from that system, and their files
+type InstalledProjects map[string]map[string]*core.Mod
+
+// GetInstalledProjects finds the projects of all files with identifiable update data
+func GetInstalledProjects(mods []*core.Mod) InstalledProjects {
+	installed := make(InstalledProjects)
+	for _, modData := range mods {
+		for k, projectID := range GetProjectIDs(modData) {
+			if installed[k] == nil {
+				installed[k] = make(map[string]*core.Mod)
+			}
+			installed[k][projectID] = modData
+		}
+	}
+	return installed
+}
+
+// GetProjectIDs returns the project IDs stored in the metadata of a file, by update system name
+func GetProjectIDs(modData *core.Mod) map[string]string {
+	projectIDs := make(map[string]string)
+	for k := range modData.Update {
+		data, ok := modData.GetParsedUpdateData(k)
+		if !ok {
+			continue
+		}
+		if id, ok := data.(core.IdentifiableUpdateData); ok {
+			projectIDs[k] = id.GetProjectID()
+		}
+	}
+	return projectIDs
+}
+
+// Find returns the file for a project from an update system, if it is installed
+func (p InstalledProjects) Find(updater string, projectID string) (*core.Mod, bool) {
+	modData, ok := p[updater][projectID]
+	return modData, ok
+}
+
+// DependencyResolver finds the installed files that satisfy dependencies. Dependencies are projects on a specific update
+// system, so the first time an update system is used, files without metadata for it are matched by the hashes stored in
+// their metadata; so dependencies are found regardless of which site each file was added from. Files are never
+// downloaded to be matched, so files whose hash formats aren't supported by the update system must be linked with
+// packwiz link to be found.
+type DependencyResolver struct {
+	installed InstalledProjects
+	mods      []*core.Mod
+	matched   map[string]bool
+}
+
+// NewDependencyResolver creates a DependencyResolver for the given installed files
+func NewDependencyResolver(mods []*core.Mod) *DependencyResolver {
+	return &DependencyResolver{
+		installed: GetInstalledProjects(mods),
+		mods:      mods,
+		matched:   make(map[string]bool),
+	}
+}
+
+// Find returns the installed file for a project from an update system, if there is one
+func (r *DependencyResolver) Find(updater string, projectID string) (*core.Mod, bool) {
+	if modData, ok := r.installed.Find(updater, projectID); ok {
+		return modData, true
+	}
+	if !r.matched[updater] {
+		r.matched[updater] = true
+		r.matchFiles(updater)
+	}
+	return r.installed.Find(updater, projectID)
+}
+
+// matchFiles finds the projects on an update system of the files that don't have metadata for it
+func (r *DependencyResolver) matchFiles(updater string) {
+	matcher, ok := core.Updaters[updater].(core.FileMatcher)
+	// Matching files requires the mod site's API
+	if !ok || viper.GetBool("offline") {
+		return
+	}
+	hashFormats := matcher.GetMatchHashFormats()
+
+	var matcherMods []*core.Mod
+	var matcherHashes []map[string]string
+	for _, modData := range r.mods {
+		if _, ok := modData.Update[updater]; ok && !modData.IsUpdateStale(updater) {
+			continue
+		}
+		if slices.Contains(hashFormats, modData.Download.HashFormat) {
+			matcherMods = append(matcherMods, modData)
+			matcherHashes = append(matcherHashes, map[string]string{modData.Download.HashFormat: modData.Download.Hash})
+		}
+	}
+	if len(matcherMods) == 0 {
+		return
+	}
+	matches, err := matcher.MatchFiles(matcherMods, matcherHashes)
+	if err != nil {
+		fmt.Printf("Warning: failed to match files with %s: %v\n", updater, err)
+		return
+	}
+	for i, match := range matches {
+		if match == nil {
+			continue
+		}
+		data, err := core.Updaters[updater].ParseUpdate(match.UpdateData)
+		if err != nil {
+			continue
+		}
+		if id, ok := data.(core.IdentifiableUpdateData); ok {
+			if r.installed[updater] == nil {
+				r.installed[updater] = make(map[string]*core.Mod)
+			}
+			r.installed[updater][id.GetProjectID()] = matcherMods[i]
+		}
+	}
+}
+
+// ModDependencies stores the dependency information of a file, and the update system it was retrieved from
+type ModDependencies struct {
+	Updater string
+	Info    core.DependencyInfo
+}
+
+// GetDependencyUpdater returns the name of the update system that the dependency information of a file is retrieved
+// from; the first update system (by name) that supports it
+func GetDependencyUpdater(modData *core.Mod) (string, bool) {
+	updaterNames := make([]string, 0, len(modData.Update))
+	for k := range modData.Update {
+		updaterNames = append(updaterNames, k)
+	}
+	slices.Sort(updaterNames)
+	for _, k := range updaterNames {
+		if _, ok := core.Updaters[k].(core.DependencyChecker); ok {
+			return k, true
+		}
+	}
+	return "", false
+}
+
+// GetDependencyInfo retrieves the dependency information of every file, from the update system given by
+// GetDependencyUpdater. Files without such an update system are omitted. Errors are returned for update systems that
+// failed completely; errors for individual files are stored in their DependencyInfo.
+func GetDependencyInfo(mods []*core.Mod, pack core.Pack) (map[*core.Mod]ModDependencies, []error) {
+	filesWithChecker := make(map[string][]*core.Mod)
+	for _, modData := range mods {
+		if k, ok := GetDependencyUpdater(modData); ok {
+			filesWithChecker[k] = append(filesWithChecker[k], modData)
+		}
+	}
+
+	results := make(map[*core.Mod]ModDependencies)
+	var errs []error
+	for k, v := range filesWithChecker {
+		infos, err := core.Updaters[k].(core.DependencyChecker).GetDependencyInfo(v, pack)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to check dependencies for %s: %w", k, err))
+			continue
+		}
+		for i, info := range infos {
+			results[v[i]] = ModDependencies{k, info}
+		}
+	}
+	return results, errs
+}
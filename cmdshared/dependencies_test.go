@@ -0,0 +1,84 @@
+package cmdshared
+
+import (
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/packwiz/packwiz/core"
+	"github.com/spf13/viper"
+)
+
+// testMatcher is a testUpdater that matches files by their sha1 hashes
+type testMatcher struct {
+	testUpdater
+	projects map[string]string
+}
+
+func (testMatcher) GetMatchHashFormats() []string {
+	return []string{"sha1"}
+}
+
+func (m testMatcher) MatchFiles(mods []*core.Mod, hashes []map[string]string) ([]*core.FileMatch, error) {
+	results := make([]*core.FileMatch, len(mods))
+	for i, v := range hashes {
+		if project, ok := m.projects[v["sha1"]]; ok {
+			results[i] = &core.FileMatch{UpdateData: map[string]interface{}{"project": project, "version": "1"}}
+		}
+	}
+	return results, nil
+}
+
+func TestDependencyResolverMatchesOtherSources(t *testing.T) {
+	httpmock.Activate(t)
+	core.Updaters["test-a"] = testUpdater{}
+	core.Updaters["test-m"] = testMatcher{projects: map[string]string{"abc": "M1"}}
+	t.Cleanup(func() {
+		delete(core.Updaters, "test-a")
+		delete(core.Updaters, "test-m")
+		viper.Set("offline", nil)
+	})
+
+	// The dependency was added from test-a, but is required by its test-m project ID
+	dependency := loadTestMod(t, `name = "Library"
+filename = "library.jar"
+[download]
+url = "https://example.com/library.jar"
+hash-format = "sha1"
+hash = "abc"
+[update.test-a]
+project = "A1"
+version = "1"
+`)
+	// Files are only matched using the hashes in their metadata, so this file can't be found on test-m
+	unmatched := loadTestMod(t, `name = "Other Library"
+filename = "other-library.jar"
+[download]
+url = "https://example.com/other-library.jar"
+hash-format = "sha256"
+hash = "def"
+[update.test-a]
+project = "A2"
+version = "1"
+`)
+	mods := []*core.Mod{dependency, unmatched}
+
+	viper.Set("offline", true)
+	if _, ok := NewDependencyResolver(mods).Find("test-m", "M1"); ok {
+		t.Error("Expected files not to be matched in offline mode")
+	}
+	viper.Set("offline", false)
+
+	resolver := NewDependencyResolver(mods)
+	if found, ok := resolver.Find("test-a", "A1"); !ok || found != dependency {
+		t.Errorf("Expected to find the dependency by its own source, got %v", found)
+	}
+	if found, ok := resolver.Find("test-m", "M1"); !ok || found != dependency {
+		t.Errorf("Expected to find the dependency by its matched project, got %v", found)
+	}
+	if _, ok := resolver.Find("test-m", "M2"); ok {
+		t.Error("Expected an unknown project not to be found")
+	}
+	if calls := httpmock.GetTotalCallCount(); calls != 0 {
+		t.Errorf("Expected files not to be downloaded for matching, got %d requests", calls)
+	}
+}
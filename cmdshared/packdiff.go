@@ -0,0 +1,377 @@
+package cmdshared
+
+import (
+	"archive/tar"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/packwiz/packwiz/core"
+	"github.com/spf13/viper"
+)
+
+// PackState is a pack with its index and all metadata files loaded
+type PackState struct {
+	Pack  core.Pack
+	Index core.Index
+	Mods  []*core.Mod
+}
+
+// LoadPackState loads a pack, its index and all of its metadata files from the given pack.toml file
+func LoadPackState(packFile string) (PackState, error) {
+	// LoadPack and LoadIndex read the pack file path from viper
+	prevPackFile := viper.GetString("pack-file")
+	viper.Set("pack-file", packFile)
+	defer viper.Set("pack-file", prevPackFile)
+
+	pack, err := core.LoadPack()
+	if err != nil {
+		return PackState{}, fmt.Errorf("failed to load %s: %w", packFile, err)
+	}
+	index, err := pack.LoadIndex()
+	if err != nil {
+		return PackState{}, fmt.Errorf("failed to load index of %s: %w", packFile, err)
+	}
+	mods, err := index.LoadAllMods()
+	if err != nil {
+		return PackState{}, err
+	}
+	return PackState{pack, index, mods}, nil
+}
+
+// LoadPackStateFromArg loads a pack from the given argument, which is either a path to a pack.toml file or a git ref;
+// in which case the current pack file is loaded from that ref. The returned function removes any temporary files.
+func LoadPackStateFromArg(arg string) (PackState, func(), error) {
+	if info, err := os.Stat(arg); err == nil && !info.IsDir() {
+		state, err := LoadPackState(arg)
+		return state, func() {}, err
+	}
+	packFile, cleanup, err := ExtractGitRef(arg, viper.GetString("pack-file"))
+	if err != nil {
+		return PackState{}, nil, err
+	}
+	state, err := LoadPackState(packFile)
+	if err != nil {
+		cleanup()
+		return PackState{}, nil, err
+	}
+	return state, cleanup, nil
+}
+
+// ExtractGitRef extracts the folder containing the given pack file, as it was at the given git ref, into a
+// temporary folder; returning the path of the pack file in this folder and a function to remove the folder
+func ExtractGitRef(ref string, packFile string) (string, func(), error) {
+	packFileAbs, err := filepath.Abs(packFile)
+	if err != nil {
+		return "", nil, err
+	}
+	packDir := filepath.Dir(packFileAbs)
+
+	prefixOut, err := runGit(packDir, "rev-parse", "--show-prefix")
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to find git repository: %w", err)
+	}
+	prefix := strings.TrimSuffix(strings.TrimSpace(string(prefixOut)), "/")
+	archive, err := runGit(packDir, "archive", "--format=tar", ref+":"+prefix)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to read git ref %s: %w", ref, err)
+	}
+
+	tempDir, err := os.MkdirTemp("", "packwiz-git-")
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to create temporary folder: %w", err)
+	}
+	cleanup := func() {
+		_ = os.RemoveAll(tempDir)
+	}
+	err = extractTar(bytes.NewReader(archive), tempDir)
+	if err != nil {
+		cleanup()
+		return "", nil, fmt.Errorf("failed to extract git ref %s: %w", ref, err)
+	}
+	return filepath.Join(tempDir, filepath.Base(packFileAbs)), cleanup, nil
+}
+
+func runGit(dir string, args ...string) ([]byte, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, errors.New(msg)
+		}
+		return nil, err
+	}
+	return out, nil
+}
+
+func extractTar(r io.Reader, dest string) error {
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dest, filepath.FromSlash(hdr.Name))
+		if !strings.HasPrefix(target, filepath.Clean(dest)+string(os.PathSeparator)) {
+			return fmt.Errorf("invalid path %s in archive", hdr.Name)
+		}
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			err = os.MkdirAll(target, 0755)
+			if err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			err = os.MkdirAll(filepath.Dir(target), 0755)
+			if err != nil {
+				return err
+			}
+			f, err := os.Create(target)
+			if err != nil {
+				return err
+			}
+			_, err = io.Copy(f, tr)
+			if err != nil {
+				_ = f.Close()
+				return err
+			}
+			err = f.Close()
+			if err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// ModIdentity identifies a mod by the project it was installed from, so it can be matched between versions of a pack
+type ModIdentity struct {
+	// Updater is the name of the update system the project is from, or empty if it has no identifiable update data
+	Updater string
+	// ProjectID is the ID of the project, or the metadata file path if Updater is empty
+	ProjectID string
+	// VersionID is the ID of the installed version, or empty if Updater is empty
+	VersionID string
+}
+
+// GetModIdentities returns the identities of a mod from every update system with identifiable update data (sorted by
+// name), or an identity using its metadata file path if there are none
+func GetModIdentities(mod *core.Mod, index *core.Index) []ModIdentity {
+	updaters := make([]string, 0, len(mod.Update))
+	for k := range mod.Update {
+		updaters = append(updaters, k)
+	}
+	slices.Sort(updaters)
+	var ids []ModIdentity
+	for _, k := range updaters {
+		data, ok := mod.GetParsedUpdateData(k)
+		if !ok {
+			continue
+		}
+		if id, ok := data.(core.IdentifiableUpdateData); ok {
+			ids = append(ids, ModIdentity{k, id.GetProjectID(), id.GetVersionID()})
+		}
+	}
+	if len(ids) > 0 {
+		return ids
+	}
+	metaPath, err := index.RelIndexPath(mod.GetFilePath())
+	if err != nil {
+		metaPath = mod.GetFilePath()
+	}
+	return []ModIdentity{{ProjectID: metaPath}}
+}
+
+// GetModIdentity returns the identity of a mod, from the first update system (by name) with identifiable update data
+func GetModIdentity(mod *core.Mod, index *core.Index) ModIdentity {
+	return GetModIdentities(mod, index)[0]
+}
+
+func (id ModIdentity) key() string {
+	return id.Updater + ":" + id.ProjectID
+}
+
+// ModChange is a mod that exists in both versions of a pack, but has changed
+type ModChange struct {
+	Old         *core.Mod
+	New         *core.Mod
+	OldIdentity ModIdentity
+	NewIdentity ModIdentity
+	// VersionChanged is true if the installed version or file has changed
+	VersionChanged bool
+	// SideChanged is true if the side has changed
+	SideChanged bool
+	// OptionChanged is true if the optional status or default value has changed
+	OptionChanged bool
+}
+
+// VersionChange is a change to a component version (e.g. minecraft or a loader) in pack.toml
+type VersionChange struct {
+	Component string
+	// Old is empty if the component was added
+	Old string
+	// New is empty if the component was removed
+	New string
+}
+
+// PackDiff is the difference between two versions of a pack
+type PackDiff struct {
+	Added             []*core.Mod
+	Removed           []*core.Mod
+	Changed           []ModChange
+	VersionChanges    []VersionChange
+	OverridesAdded    []string
+	OverridesRemoved  []string
+	OverridesModified []string
+}
+
+// IsEmpty returns true if there are no differences
+func (d PackDiff) IsEmpty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0 && len(d.VersionChanges) == 0 &&
+		len(d.OverridesAdded) == 0 && len(d.OverridesRemoved) == 0 && len(d.OverridesModified) == 0
+}
+
+// DiffPacks compares two versions of a pack, matching mods by their identities (see GetModIdentities)
+func DiffPacks(oldState PackState, newState PackState) PackDiff {
+	var diff PackDiff
+
+	// Mods are matched when they share any identity, so that mods which had another source added (e.g. with link) or
+	// removed are still matched
+	newMods := make(map[string][]*core.Mod)
+	newIdentities := make(map[*core.Mod][]ModIdentity)
+	for _, mod := range newState.Mods {
+		ids := GetModIdentities(mod, &newState.Index)
+		newIdentities[mod] = ids
+		for _, id := range ids {
+			newMods[id.key()] = append(newMods[id.key()], mod)
+		}
+	}
+	matched := make(map[*core.Mod]bool)
+	for _, oldMod := range oldState.Mods {
+		var newMod *core.Mod
+		var oldId, newId ModIdentity
+	findMatch:
+		for _, id := range GetModIdentities(oldMod, &oldState.Index) {
+			for _, mod := range newMods[id.key()] {
+				if matched[mod] {
+					continue
+				}
+				newMod = mod
+				oldId = id
+				for _, v := range newIdentities[mod] {
+					if v.key() == id.key() {
+						newId = v
+					}
+				}
+				break findMatch
+			}
+		}
+		if newMod == nil {
+			diff.Removed = append(diff.Removed, oldMod)
+			continue
+		}
+		matched[newMod] = true
+
+		change := ModChange{
+			Old:         oldMod,
+			New:         newMod,
+			OldIdentity: oldId,
+			NewIdentity: newId,
+		}
+		if oldId.VersionID != newId.VersionID || oldMod.FileName != newMod.FileName ||
+			!strings.EqualFold(oldMod.Download.Hash, newMod.Download.Hash) {
+			change.VersionChanged = true
+		}
+		change.SideChanged = normaliseSide(oldMod.Side) != normaliseSide(newMod.Side)
+		change.OptionChanged = OptionString(oldMod.Option) != OptionString(newMod.Option)
+		if change.VersionChanged || change.SideChanged || change.OptionChanged {
+			diff.Changed = append(diff.Changed, change)
+		}
+	}
+	for _, mod := range newState.Mods {
+		if !matched[mod] {
+			diff.Added = append(diff.Added, mod)
+		}
+	}
+
+	sortMods := func(a, b *core.Mod) int {
+		return strings.Compare(strings.ToLower(a.Name), strings.ToLower(b.Name))
+	}
+	slices.SortFunc(diff.Added, sortMods)
+	slices.SortFunc(diff.Removed, sortMods)
+	slices.SortFunc(diff.Changed, func(a, b ModChange) int {
+		return sortMods(a.New, b.New)
+	})
+
+	for component, oldVersion := range oldState.Pack.Versions {
+		if newVersion := newState.Pack.Versions[component]; newVersion != oldVersion {
+			diff.VersionChanges = append(diff.VersionChanges, VersionChange{component, oldVersion, newVersion})
+		}
+	}
+	for component, newVersion := range newState.Pack.Versions {
+		if _, ok := oldState.Pack.Versions[component]; !ok {
+			diff.VersionChanges = append(diff.VersionChanges, VersionChange{component, "", newVersion})
+		}
+	}
+	slices.SortFunc(diff.VersionChanges, func(a, b VersionChange) int {
+		return strings.Compare(a.Component, b.Component)
+	})
+
+	for p, v := range oldState.Index.Files {
+		if v.IsMetaFile() {
+			continue
+		}
+		newFile, ok := newState.Index.Files[p]
+		if !ok || newFile.IsMetaFile() {
+			diff.OverridesRemoved = append(diff.OverridesRemoved, p)
+			continue
+		}
+		_, oldHash := v.GetHash()
+		_, newHash := newFile.GetHash()
+		if !strings.EqualFold(oldHash, newHash) {
+			diff.OverridesModified = append(diff.OverridesModified, p)
+		}
+	}
+	for p, v := range newState.Index.Files {
+		if v.IsMetaFile() {
+			continue
+		}
+		if oldFile, ok := oldState.Index.Files[p]; !ok || oldFile.IsMetaFile() {
+			diff.OverridesAdded = append(diff.OverridesAdded, p)
+		}
+	}
+	slices.Sort(diff.OverridesAdded)
+	slices.Sort(diff.OverridesRemoved)
+	slices.Sort(diff.OverridesModified)
+
+	return diff
+}
+
+func normaliseSide(side string) string {
+	if side == "" {
+		return core.UniversalSide
+	}
+	return side
+}
+
+// OptionString returns a human-readable description of a mod's optional status
+func OptionString(option *core.ModOption) string {
+	if option == nil || !option.Optional {
+		return "required"
+	}
+	if option.Default {
+		return "optional (enabled by default)"
+	}
+	return "optional"
+}
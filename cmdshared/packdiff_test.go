@@ -0,0 +1,111 @@
+package cmdshared
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/packwiz/packwiz/core"
+)
+
+type testUpdateData struct {
+	project string
+	version string
+}
+
+func (d testUpdateData) GetProjectID() string { return d.project }
+func (d testUpdateData) GetVersionID() string { return d.version }
+
+type testUpdater struct{}
+
+func (testUpdater) ParseUpdate(data map[string]interface{}) (interface{}, error) {
+	project, _ := data["project"].(string)
+	version, _ := data["version"].(string)
+	return testUpdateData{project, version}, nil
+}
+
+func (testUpdater) CheckUpdate([]*core.Mod, core.Pack) ([]core.UpdateCheck, error) {
+	return nil, nil
+}
+
+func (testUpdater) DoUpdate([]*core.Mod, []interface{}) error {
+	return nil
+}
+
+func loadTestMod(t *testing.T, contents string) *core.Mod {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "mod.pw.toml")
+	if err := os.WriteFile(path, []byte(contents), 0644); err != nil {
+		t.Fatal(err)
+	}
+	mod, err := core.LoadMod(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &mod
+}
+
+func TestDiffPacksMatchesAnySharedIdentity(t *testing.T) {
+	core.Updaters["test-a"] = testUpdater{}
+	core.Updaters["test-b"] = testUpdater{}
+	t.Cleanup(func() {
+		delete(core.Updaters, "test-a")
+		delete(core.Updaters, "test-b")
+	})
+
+	oldMod := loadTestMod(t, `name = "Linked"
+filename = "linked.jar"
+[download]
+url = "https://example.com/linked.jar"
+hash-format = "sha1"
+hash = "abc"
+[update.test-b]
+project = "B1"
+version = "1"
+`)
+	// A source sorting before the original one was added (e.g. with link), so the first identity has changed
+	newMod := loadTestMod(t, `name = "Linked"
+filename = "linked.jar"
+[download]
+url = "https://example.com/linked.jar"
+hash-format = "sha1"
+hash = "abc"
+[update.test-a]
+project = "A1"
+version = "1"
+[update.test-b]
+project = "B1"
+version = "1"
+`)
+
+	diff := DiffPacks(PackState{Mods: []*core.Mod{oldMod}}, PackState{Mods: []*core.Mod{newMod}})
+	if len(diff.Added) != 0 || len(diff.Removed) != 0 {
+		t.Fatalf("Expected the mod to be matched, got %d added and %d removed", len(diff.Added), len(diff.Removed))
+	}
+	if len(diff.Changed) != 0 {
+		t.Errorf("Expected no changes, got %+v", diff.Changed)
+	}
+
+	// The shared identity is used to compare versions
+	updated := loadTestMod(t, `name = "Linked"
+filename = "linked-2.jar"
+[download]
+url = "https://example.com/linked-2.jar"
+hash-format = "sha1"
+hash = "def"
+[update.test-a]
+project = "A1"
+version = "2"
+[update.test-b]
+project = "B1"
+version = "2"
+`)
+	diff = DiffPacks(PackState{Mods: []*core.Mod{oldMod}}, PackState{Mods: []*core.Mod{updated}})
+	if len(diff.Changed) != 1 || !diff.Changed[0].VersionChanged {
+		t.Fatalf("Expected a version change, got %+v", diff)
+	}
+	change := diff.Changed[0]
+	if change.OldIdentity.Updater != "test-b" || change.NewIdentity != (ModIdentity{"test-b", "B1", "2"}) {
+		t.Errorf("Expected both identities to use the shared test-b source, got %+v and %+v", change.OldIdentity, change.NewIdentity)
+	}
+}
@@ -26,3 +26,20 @@ func PromptYesNo(prompt string) bool {
 	}
 	return true
 }
+
+// PromptYesNoDefaultNo is like PromptYesNo, but defaults to no (including in non-interactive mode)
+func PromptYesNoDefaultNo(prompt string) bool {
+	fmt.Print(prompt)
+	if viper.GetBool("non-interactive") {
+		fmt.Println("N (non-interactive mode)")
+		return false
+	}
+	answer, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil {
+		fmt.Printf("Failed to prompt user: %v\n", err)
+		os.Exit(1)
+	}
+
+	ansNormal := strings.ToLower(strings.TrimSpace(answer))
+	return len(ansNormal) > 0 && ansNormal[0] == 'y'
+}
@@ -0,0 +1,138 @@
+package cmdshared
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/packwiz/packwiz/core"
+)
+
+// GetPrimaryUpdater returns the name of the update system that should be used to update a file, when it has metadata
+// for more than one update system (e.g. after running packwiz link). The update system that the file is downloaded
+// from is preferred, as updating replaces the download with one from the chosen update system.
+func GetPrimaryUpdater(modData *core.Mod) (string, bool) {
+	var names []string
+	for k := range modData.Update {
+		if _, ok := core.Updaters[k]; ok && !modData.IsUpdateStale(k) {
+			names = append(names, k)
+		}
+	}
+	if len(names) == 0 {
+		return "", false
+	}
+	slices.Sort(names)
+	if modData.Download.Mode == core.ModeCF && slices.Contains(names, "curseforge") {
+		return "curseforge", true
+	}
+	if modData.Download.Mode != core.ModeCF && slices.Contains(names, "modrinth") {
+		return "modrinth", true
+	}
+	return names[0], true
+}
+
+// RelinkUpdatedFiles finds the updated files on the update systems other than the given one (that the files were
+// updated with), replacing their metadata, as it refers to the version that was installed before updating. Metadata
+// that can't be replaced is kept but marked as stale, and the names of these update systems are returned for each file.
+func RelinkUpdatedFiles(mods []*core.Mod, updater string) map[*core.Mod][]string {
+	stale := make(map[*core.Mod][]string)
+	matcherMods := make(map[string][]*core.Mod)
+	var downloadMods []*core.Mod
+	var hashFormats []string
+	for _, modData := range mods {
+		needsDownload := false
+		for k := range modData.Update {
+			if k == updater {
+				continue
+			}
+			matcher, ok := core.Updaters[k].(core.FileMatcher)
+			if !ok {
+				modData.MarkUpdateStale(k)
+				stale[modData] = append(stale[modData], k)
+				continue
+			}
+			matcherMods[k] = append(matcherMods[k], modData)
+			for _, format := range matcher.GetMatchHashFormats() {
+				if format == modData.Download.HashFormat {
+					continue
+				}
+				needsDownload = true
+				if !slices.Contains(hashFormats, format) {
+					hashFormats = append(hashFormats, format)
+				}
+			}
+		}
+		if needsDownload {
+			downloadMods = append(downloadMods, modData)
+		}
+	}
+
+	hashes := make(map[*core.Mod]map[string]string)
+	for _, modData := range mods {
+		hashes[modData] = map[string]string{modData.Download.HashFormat: modData.Download.Hash}
+	}
+	if len(downloadMods) > 0 {
+		// The hashes used for matching aren't stored, so the updated files need to be downloaded to get them
+		session, err := core.CreateDownloadSession(downloadMods, hashFormats)
+		if err != nil {
+			fmt.Printf("Error retrieving updated files: %v\n", err)
+		} else {
+			for dl := range session.StartDownloads() {
+				if dl.Error != nil {
+					fmt.Printf("Download of %s (%s) failed: %v\n", dl.Mod.Name, dl.Mod.FileName, dl.Error)
+					continue
+				}
+				_ = dl.File.Close()
+				for format, hash := range dl.Hashes {
+					hashes[dl.Mod][format] = hash
+				}
+			}
+			err = session.SaveIndex()
+			if err != nil {
+				fmt.Printf("Error saving cache index: %v\n", err)
+			}
+		}
+	}
+
+	for k, v := range matcherMods {
+		matcher := core.Updaters[k].(core.FileMatcher)
+		var matchMods []*core.Mod
+		var matchHashes []map[string]string
+		for _, modData := range v {
+			complete := true
+			for _, format := range matcher.GetMatchHashFormats() {
+				if _, ok := hashes[modData][format]; !ok {
+					complete = false
+				}
+			}
+			if complete {
+				matchMods = append(matchMods, modData)
+				matchHashes = append(matchHashes, hashes[modData])
+			} else {
+				modData.MarkUpdateStale(k)
+				stale[modData] = append(stale[modData], k)
+			}
+		}
+		if len(matchMods) == 0 {
+			continue
+		}
+
+		matches, err := matcher.MatchFiles(matchMods, matchHashes)
+		if err != nil {
+			fmt.Printf("Failed to match updated files with %s: %v\n", k, err)
+			matches = make([]*core.FileMatch, len(matchMods))
+		}
+		for i, match := range matches {
+			modData := matchMods[i]
+			if match == nil {
+				modData.MarkUpdateStale(k)
+				stale[modData] = append(stale[modData], k)
+				continue
+			}
+			modData.Update[k] = match.UpdateData
+		}
+	}
+	for _, v := range stale {
+		slices.Sort(v)
+	}
+	return stale
+}
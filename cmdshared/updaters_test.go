@@ -0,0 +1,89 @@
+package cmdshared
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/packwiz/packwiz/core"
+)
+
+func TestRelinkUpdatedFiles(t *testing.T) {
+	core.Updaters["test-a"] = testUpdater{}
+	core.Updaters["test-b"] = testUpdater{}
+	core.Updaters["test-m"] = testMatcher{projects: map[string]string{"abc": "M1"}}
+	t.Cleanup(func() {
+		delete(core.Updaters, "test-a")
+		delete(core.Updaters, "test-b")
+		delete(core.Updaters, "test-m")
+	})
+
+	// Both files have been updated using test-a, so their other metadata refers to the previous version
+	matched := loadTestMod(t, `name = "Matched"
+filename = "matched.jar"
+[download]
+url = "https://example.com/matched.jar"
+hash-format = "sha1"
+hash = "abc"
+[update.test-a]
+project = "A1"
+version = "2"
+[update.test-b]
+project = "B1"
+version = "1"
+[update.test-m]
+project = "M1"
+version = "0"
+`)
+	unmatched := loadTestMod(t, `name = "Unmatched"
+filename = "unmatched.jar"
+[download]
+url = "https://example.com/unmatched.jar"
+hash-format = "sha1"
+hash = "def"
+[update.test-a]
+project = "A2"
+version = "2"
+[update.test-m]
+project = "M2"
+version = "0"
+`)
+
+	stale := RelinkUpdatedFiles([]*core.Mod{matched, unmatched}, "test-a")
+
+	if !slices.Equal(stale[matched], []string{"test-b"}) {
+		t.Errorf("Expected only test-b to be stale for the matched file, got %v", stale[matched])
+	}
+	if matched.IsUpdateStale("test-m") || matched.Update["test-m"]["version"] != "1" {
+		t.Errorf("Expected test-m metadata to be replaced with the matched file, got %v", matched.Update["test-m"])
+	}
+	if !matched.IsUpdateStale("test-b") || matched.Update["test-b"]["project"] != "B1" {
+		t.Errorf("Expected test-b metadata to be kept and marked as stale, got %v", matched.Update["test-b"])
+	}
+	if _, ok := matched.GetParsedUpdateData("test-b"); ok {
+		t.Error("Expected stale test-b metadata not to be used")
+	}
+	if _, _, err := matched.Write(); err != nil {
+		t.Fatal(err)
+	}
+	reloaded, err := core.LoadMod(matched.GetFilePath())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := reloaded.GetParsedUpdateData("test-b"); ok || !reloaded.IsUpdateStale("test-b") {
+		t.Error("Expected test-b metadata to be kept as stale when the file is loaded again")
+	}
+
+	if !slices.Equal(stale[unmatched], []string{"test-m"}) {
+		t.Errorf("Expected test-m to be stale for the unmatched file, got %v", stale[unmatched])
+	}
+	if unmatched.IsUpdateStale("test-a") {
+		t.Error("Expected the metadata of the update system used for updating not to be stale")
+	}
+
+	// Stale metadata is not used for updating
+	unmatched.Download.Mode = ""
+	delete(unmatched.Update, "test-a")
+	if updater, ok := GetPrimaryUpdater(unmatched); ok {
+		t.Errorf("Expected no update system for a file with only stale metadata, got %s", updater)
+	}
+}
@@ -0,0 +1,520 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+// cacheLockFile is the file in the cache folder that is locked while the cache index is read or written
+const cacheLockFile = "index.lock"
+
+// lockCache acquires the lock on the cache index, which is shared by every packwiz process using the same cache folder
+func lockCache(cachePath string) (func(), error) {
+	f, err := os.OpenFile(filepath.Join(cachePath, cacheLockFile), os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open cache lock file: %w", err)
+	}
+	err = lockFile(f)
+	if err != nil {
+		_ = f.Close()
+		return nil, fmt.Errorf("failed to lock cache: %w", err)
+	}
+	return func() {
+		_ = unlockFile(f)
+		_ = f.Close()
+	}, nil
+}
+
+// readCacheIndex reads the cache index from the cache folder, updating it to the latest version; the cache must be
+// locked
+func readCacheIndex(cachePath string) (*CacheIndex, error) {
+	cacheIndex := &CacheIndex{Version: cacheLatestVersion, Hashes: make(map[string][]string)}
+	cacheIndexData, err := os.ReadFile(filepath.Join(cachePath, "index.json"))
+	if err != nil {
+		if !os.IsNotExist(err) {
+			return nil, fmt.Errorf("failed to read cache index file: %w", err)
+		}
+	} else {
+		err = json.Unmarshal(cacheIndexData, cacheIndex)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read cache index file: %w", err)
+		}
+	}
+
+	// Ensure some parts of the index are initialised
+	_, hasCacheHashFmt := cacheIndex.Hashes[cacheHashFormat]
+	if !hasCacheHashFmt {
+		cacheIndex.Hashes[cacheHashFormat] = make([]string, 0)
+	}
+	cacheIndex.cachePath = cachePath
+
+	// Ensure the cache's version is up-to-date
+	cacheIndex.updateVersion()
+	if cacheIndex.Version > cacheLatestVersion {
+		return nil, fmt.Errorf("cache index is too new (version %v)", cacheIndex.Version)
+	}
+	return cacheIndex, nil
+}
+
+// Save writes the cache index to the cache folder, merging it with any changes saved by other packwiz processes since
+// it was loaded
+func (c *CacheIndex) Save() error {
+	unlock, err := lockCache(c.cachePath)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	saved, err := readCacheIndex(c.cachePath)
+	if err != nil {
+		return err
+	}
+	c.mu.Lock()
+	c.merge(saved)
+	data, err := json.Marshal(c)
+	c.mu.Unlock()
+	if err != nil {
+		return fmt.Errorf("failed to serialise index: %w", err)
+	}
+
+	// Write to a temporary file first, so the index is never left partially written
+	tempPath := filepath.Join(c.cachePath, "index.json.tmp")
+	err = os.WriteFile(tempPath, data, 0644)
+	if err == nil {
+		err = os.Rename(tempPath, filepath.Join(c.cachePath, "index.json"))
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write index: %w", err)
+	}
+	return nil
+}
+
+// merge adds the entries and hashes of the saved index that aren't in this index, and removes entries that were
+// removed from the saved index; c.mu must be held
+func (c *CacheIndex) merge(saved *CacheIndex) {
+	indexes := make(map[string]int)
+	for i, hash := range c.Hashes[cacheHashFormat] {
+		if hash != "" {
+			indexes[hash] = i
+		}
+	}
+	savedHashes := make(map[string]bool)
+	for i, hash := range saved.Hashes[cacheHashFormat] {
+		if hash == "" || c.removed[hash] {
+			continue
+		}
+		savedHashes[hash] = true
+		var accessTime int64
+		if i < len(saved.AccessTimes) {
+			accessTime = saved.AccessTimes[i]
+		}
+
+		j, ok := indexes[hash]
+		if !ok {
+			// Added by another process; indexes from nextHashIdx may be used by handles that aren't in the index yet
+			j = max(len(c.Hashes[cacheHashFormat]), c.nextHashIdx)
+			c.nextHashIdx = max(c.nextHashIdx, j+1)
+		}
+		for hashFormat, v := range saved.getHashesMap(i) {
+			hashList := c.Hashes[hashFormat]
+			if j >= len(hashList) {
+				hashList = append(hashList, make([]string, j+1-len(hashList))...)
+				c.Hashes[hashFormat] = hashList
+			}
+			if hashList[j] == "" {
+				hashList[j] = v
+			}
+		}
+		if j >= len(c.AccessTimes) {
+			c.AccessTimes = append(c.AccessTimes, make([]int64, j+1-len(c.AccessTimes))...)
+		}
+		c.AccessTimes[j] = max(c.AccessTimes[j], accessTime)
+	}
+
+	// Entries that aren't in the saved index were either added by this process or removed by another process
+	for hash, i := range indexes {
+		if savedHashes[hash] {
+			continue
+		}
+		if _, err := os.Stat(filepath.Join(c.cachePath, hash[:2], hash[2:])); os.IsNotExist(err) {
+			for hashFormat, hashList := range c.Hashes {
+				if i < len(hashList) {
+					c.Hashes[hashFormat][i] = ""
+				}
+			}
+		}
+	}
+}
+
+// RelPath returns the path of the file of a cache entry relative to the cache folder, in forward slash format
+func (h *CacheIndexHandle) RelPath() string {
+	cacheFileHash := h.Hashes[cacheHashFormat]
+	return cacheFileHash[:2] + "/" + cacheFileHash[2:]
+}
+
+// NewCacheIndexSubset creates an empty cache index with the same version as this index, to store a subset of its entries
+// (e.g. for exporting them)
+func (c *CacheIndex) NewCacheIndexSubset() *CacheIndex {
+	return &CacheIndex{Version: c.Version, Hashes: map[string][]string{cacheHashFormat: {}}}
+}
+
+// AddEntry adds the hashes of a cache entry to this index, without a file in the cache folder
+func (c *CacheIndex) AddEntry(hashes map[string]string) {
+	i := len(c.Hashes[cacheHashFormat])
+	for hashFormat, hash := range hashes {
+		hashList := c.Hashes[hashFormat]
+		hashList = append(hashList, make([]string, i+1-len(hashList))...)
+		hashList[i] = hash
+		c.Hashes[hashFormat] = hashList
+	}
+}
+
+// GetEntries returns a handle for every entry in this index
+func (c *CacheIndex) GetEntries() []*CacheIndexHandle {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var entries []*CacheIndexHandle
+	for i, hash := range c.Hashes[cacheHashFormat] {
+		// Entries with invalid hashes (e.g. in an archive index) have no path in the cache folder
+		if !validCacheHash(hash) {
+			continue
+		}
+		entries = append(entries, &CacheIndexHandle{
+			index:   c,
+			hashIdx: i,
+			Hashes:  c.getHashesMap(i),
+		})
+	}
+	return entries
+}
+
+// Validate checks that the hashes of the entries in this index are valid, so that they can be used as paths in the
+// cache folder
+func (c *CacheIndex) Validate() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, hash := range c.Hashes[cacheHashFormat] {
+		if hash != "" && !validCacheHash(hash) {
+			return fmt.Errorf("invalid %s hash %q", cacheHashFormat, hash)
+		}
+	}
+	return nil
+}
+
+// validCacheHash returns true if a hash is a lowercase hex encoded hash in the cache hash format
+func validCacheHash(hash string) bool {
+	if len(hash) != 64 {
+		return false
+	}
+	for _, c := range hash {
+		if (c < '0' || c > '9') && (c < 'a' || c > 'f') {
+			return false
+		}
+	}
+	return true
+}
+
+// ImportFile adds a file to the cache, validating it against its hash in the cache hash format (and any other hashes
+// given) and calculating the hash formats given without a value. Returns false if the file was already in the cache.
+func (c *CacheIndex) ImportFile(hashes map[string]string, src io.Reader) (bool, error) {
+	cacheHash, ok := hashes[cacheHashFormat]
+	if !ok {
+		return false, fmt.Errorf("no %s hash given for imported file", cacheHashFormat)
+	}
+	if !validCacheHash(cacheHash) {
+		return false, fmt.Errorf("invalid %s hash %q given for imported file", cacheHashFormat, cacheHash)
+	}
+	if c.GetHandleFromHash(cacheHashFormat, cacheHash) != nil {
+		return false, nil
+	}
+
+	tempFile, err := os.CreateTemp(filepath.Join(c.cachePath, "temp"), "import-tmp")
+	if err != nil {
+		return false, fmt.Errorf("failed to create temporary file for import: %w", err)
+	}
+	newHashes, err := importHashes(hashes, tempFile, src)
+	if err != nil {
+		_ = tempFile.Close()
+		_ = os.Remove(tempFile.Name())
+		return false, err
+	}
+
+	c.fileMu.Lock()
+	defer c.fileMu.Unlock()
+	handle, alreadyExists := c.NewHandleFromHashes(newHashes)
+	if alreadyExists {
+		_ = tempFile.Close()
+		_ = os.Remove(tempFile.Name())
+		return false, nil
+	}
+	file, err := handle.CreateFromTemp(tempFile)
+	if err != nil {
+		_ = os.Remove(tempFile.Name())
+		return false, fmt.Errorf("failed to move file %s to cache: %w", handle.Path(), err)
+	}
+	err = file.Close()
+	if err != nil {
+		return false, err
+	}
+	_ = handle.UpdateIndex()
+	return true, nil
+}
+
+// importHashes copies an imported file to dst, calculating its hashes in the cache hash format and each format given,
+// and returns an error if any of them don't match the given hashes
+func importHashes(hashes map[string]string, dst io.Writer, src io.Reader) (map[string]string, error) {
+	hashers := make(map[string]HashStringer, len(hashes))
+	writers := []io.Writer{dst}
+	for hashFormat := range hashes {
+		hasher, err := GetHashImpl(hashFormat)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get hash format %s", hashFormat)
+		}
+		hashers[hashFormat] = hasher
+		writers = append(writers, hasher)
+	}
+	_, err := io.Copy(io.MultiWriter(writers...), src)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+
+	newHashes := make(map[string]string, len(hashers))
+	for hashFormat, hasher := range hashers {
+		calculatedHash := hasher.HashToString(hasher.Sum(nil))
+		// Hashes other than the cache hash format may be left empty, to be calculated
+		if (hashes[hashFormat] != "" || hashFormat == cacheHashFormat) && !strings.EqualFold(calculatedHash, hashes[hashFormat]) {
+			return nil, fmt.Errorf("%s hash of imported file does not match with expected hash!\n file hash: %s\n expected hash: %s\n",
+				hashFormat, calculatedHash, hashes[hashFormat])
+		}
+		newHashes[hashFormat] = calculatedHash
+	}
+	return newHashes, nil
+}
+
+// CacheEvictionPolicy specifies which entries are removed from the cache by FindEvictable
+type CacheEvictionPolicy struct {
+	// MaxSize is the maximum total size of the files in the cache in bytes, or 0 for no limit
+	MaxSize int64
+	// MaxAge is the maximum time since a file was last used, or 0 for no limit
+	MaxAge time.Duration
+	// Keep stores entries that are never removed (e.g. files used by a pack)
+	Keep []*CacheIndexHandle
+}
+
+// FindEvictable returns the entries to remove from the cache to satisfy the given policy, removing the least recently
+// used entries first, and the total size of their files. Entries with missing files are always returned.
+func (c *CacheIndex) FindEvictable(policy CacheEvictionPolicy) ([]*CacheIndexHandle, int64, error) {
+	type entry struct {
+		handle     *CacheIndexHandle
+		size       int64
+		accessTime time.Time
+	}
+	kept := make(map[int]bool)
+	for _, v := range policy.Keep {
+		kept[v.hashIdx] = true
+	}
+
+	var entries []entry
+	var evictable []*CacheIndexHandle
+	var totalSize, evictableSize int64
+	for _, handle := range c.GetEntries() {
+		stats, err := os.Stat(handle.Path())
+		if err != nil {
+			if os.IsNotExist(err) {
+				evictable = append(evictable, handle)
+				continue
+			}
+			return nil, 0, err
+		}
+		accessTime := stats.ModTime()
+		c.mu.Lock()
+		if handle.hashIdx < len(c.AccessTimes) && c.AccessTimes[handle.hashIdx] != 0 {
+			accessTime = time.Unix(c.AccessTimes[handle.hashIdx], 0)
+		}
+		c.mu.Unlock()
+		entries = append(entries, entry{handle, stats.Size(), accessTime})
+		totalSize += stats.Size()
+	}
+	slices.SortFunc(entries, func(a, b entry) int {
+		return a.accessTime.Compare(b.accessTime)
+	})
+
+	for _, v := range entries {
+		if kept[v.handle.hashIdx] {
+			continue
+		}
+		tooOld := policy.MaxAge > 0 && time.Since(v.accessTime) > policy.MaxAge
+		tooLarge := policy.MaxSize > 0 && totalSize > policy.MaxSize
+		if tooOld || tooLarge {
+			evictable = append(evictable, v.handle)
+			totalSize -= v.size
+			evictableSize += v.size
+		}
+	}
+	return evictable, evictableSize, nil
+}
+
+// CacheQuarantineFolder is the folder in the cache that corrupt files are moved to by CacheIndexHandle.Quarantine
+const CacheQuarantineFolder = "quarantine"
+
+// CacheVerifyResult stores the problems found in the cache by CacheIndex.Verify
+type CacheVerifyResult struct {
+	// Checked is the number of entries that were checked
+	Checked int
+	// Corrupt stores entries with files that don't match their hashes
+	Corrupt []*CacheIndexHandle
+	// Missing stores entries without a file
+	Missing []*CacheIndexHandle
+	// Orphaned stores the paths of files in the cache that aren't in the index
+	Orphaned []string
+}
+
+// Verify rehashes every file in the cache, checking it against every hash stored in the index, and finds files in the
+// cache folder that aren't in the index
+func (c *CacheIndex) Verify() (CacheVerifyResult, error) {
+	var result CacheVerifyResult
+	known := make(map[string]bool)
+	for _, handle := range c.GetEntries() {
+		known[filepath.FromSlash(handle.RelPath())] = true
+		result.Checked++
+		ok, err := handle.verifyFile()
+		if err != nil {
+			if os.IsNotExist(err) {
+				result.Missing = append(result.Missing, handle)
+				continue
+			}
+			return result, fmt.Errorf("failed to read %s: %w", handle.Path(), err)
+		}
+		if !ok {
+			result.Corrupt = append(result.Corrupt, handle)
+		}
+	}
+
+	entries, err := os.ReadDir(c.cachePath)
+	if err != nil {
+		return result, err
+	}
+	for _, dir := range entries {
+		// Files are stored in folders named by the first two characters of their hash
+		if !dir.IsDir() || len(dir.Name()) != 2 {
+			continue
+		}
+		files, err := os.ReadDir(filepath.Join(c.cachePath, dir.Name()))
+		if err != nil {
+			return result, err
+		}
+		for _, file := range files {
+			rel := filepath.Join(dir.Name(), file.Name())
+			if !known[rel] {
+				result.Orphaned = append(result.Orphaned, filepath.Join(c.cachePath, rel))
+			}
+		}
+	}
+	return result, nil
+}
+
+func (h *CacheIndexHandle) verifyFile() (bool, error) {
+	file, err := h.Open()
+	if err != nil {
+		return false, err
+	}
+	defer file.Close()
+
+	hashers := make(map[string]HashStringer)
+	var writers []io.Writer
+	for hashFormat, hash := range h.Hashes {
+		if hash == "" {
+			continue
+		}
+		hasher, err := GetHashImpl(hashFormat)
+		if err != nil {
+			// Unknown hash formats can't be checked
+			continue
+		}
+		hashers[hashFormat] = hasher
+		writers = append(writers, hasher)
+	}
+	_, err = io.Copy(io.MultiWriter(writers...), file)
+	if err != nil {
+		return false, err
+	}
+	for hashFormat, hasher := range hashers {
+		if !strings.EqualFold(hasher.HashToString(hasher.Sum(nil)), h.Hashes[hashFormat]) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// Quarantine moves this handle's file to the quarantine folder of the cache, and clears its entry in the index
+func (h *CacheIndexHandle) Quarantine() error {
+	h.index.fileMu.Lock()
+	defer h.index.fileMu.Unlock()
+	path := h.Path()
+	h.clearEntry()
+	return h.index.quarantineFile(path)
+}
+
+// QuarantineFile moves a file that isn't in the index to the quarantine folder of the cache
+func (c *CacheIndex) QuarantineFile(path string) error {
+	c.fileMu.Lock()
+	defer c.fileMu.Unlock()
+	return c.quarantineFile(path)
+}
+
+func (c *CacheIndex) quarantineFile(path string) error {
+	rel, err := filepath.Rel(c.cachePath, path)
+	if err != nil {
+		return err
+	}
+	dest := filepath.Join(c.cachePath, CacheQuarantineFolder, strings.ReplaceAll(rel, string(filepath.Separator), ""))
+	err = os.MkdirAll(filepath.Dir(dest), 0755)
+	if err != nil {
+		return err
+	}
+	err = os.Rename(path, dest)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to move %s to quarantine: %w", path, err)
+	}
+	return nil
+}
+
+// ImportOrphan adds a file in the cache folder that isn't in the index back to the index, if its contents match the
+// hash it is named by; returns false (leaving the file in place) if they don't match
+func (c *CacheIndex) ImportOrphan(path string) (bool, error) {
+	rel, err := filepath.Rel(c.cachePath, path)
+	if err != nil {
+		return false, err
+	}
+	hash := strings.ToLower(strings.ReplaceAll(rel, string(filepath.Separator), ""))
+	hasher, err := GetHashImpl(cacheHashFormat)
+	if err != nil {
+		return false, err
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return false, err
+	}
+	_, err = io.Copy(hasher, file)
+	_ = file.Close()
+	if err != nil {
+		return false, err
+	}
+	if hasher.HashToString(hasher.Sum(nil)) != hash {
+		return false, nil
+	}
+
+	c.fileMu.Lock()
+	defer c.fileMu.Unlock()
+	handle, alreadyExists := c.NewHandleFromHashes(map[string]string{cacheHashFormat: hash})
+	if !alreadyExists {
+		_ = handle.UpdateIndex()
+	}
+	return true, nil
+}
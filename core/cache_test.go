@@ -0,0 +1,321 @@
+package core
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+func TestImportFile(t *testing.T) {
+	viper.Set("cache.directory", t.TempDir())
+	t.Cleanup(func() {
+		viper.Set("cache.directory", nil)
+	})
+
+	cacheIndex, err := LoadCacheIndex()
+	if err != nil {
+		t.Fatal(err)
+	}
+	content := []byte("imported file")
+	hash := sha256.Sum256(content)
+	hashes := map[string]string{"sha256": hex.EncodeToString(hash[:]), "sha1": ""}
+
+	_, err = cacheIndex.ImportFile(map[string]string{"sha256": hex.EncodeToString(make([]byte, 32))}, bytes.NewReader(content))
+	if err == nil {
+		t.Error("Expected an error when importing a file with the wrong hash")
+	}
+	_, err = cacheIndex.ImportFile(map[string]string{"sha256": "0"}, bytes.NewReader(content))
+	if err == nil {
+		t.Error("Expected an error when importing a file with an invalid hash")
+	}
+	_, err = cacheIndex.ImportFile(map[string]string{"sha256": hashes["sha256"], "sha1": "0000"}, bytes.NewReader(content))
+	if err == nil {
+		t.Error("Expected an error when importing a file with the wrong sha1 hash")
+	}
+	if entries := cacheIndex.GetEntries(); len(entries) != 0 {
+		t.Errorf("Expected files with the wrong hash not to be imported, got %d entries", len(entries))
+	}
+
+	added, err := cacheIndex.ImportFile(hashes, bytes.NewReader(content))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !added {
+		t.Error("Expected the file to be added to the cache")
+	}
+	added, err = cacheIndex.ImportFile(hashes, bytes.NewReader(content))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if added {
+		t.Error("Expected the file to already be in the cache")
+	}
+
+	handle := cacheIndex.GetHandleFromHash("sha256", hashes["sha256"])
+	if handle == nil {
+		t.Fatal("Expected the imported file to be in the cache index")
+	}
+	if handle.Hashes["sha1"] == "" {
+		t.Error("Expected the sha1 hash of the imported file to be calculated")
+	}
+	if entries := cacheIndex.GetEntries(); len(entries) != 1 {
+		t.Errorf("Expected 1 cache index entry, got %d", len(entries))
+	}
+}
+
+func TestGetEntriesInvalidHash(t *testing.T) {
+	valid := strings.Repeat("ab", 32)
+	// Indexes read from archives may contain any hashes
+	index := CacheIndex{Hashes: map[string][]string{"sha256": {"", "0", valid, "../" + valid[3:], strings.ToUpper(valid)}}}
+	entries := index.GetEntries()
+	if len(entries) != 1 || entries[0].RelPath() != valid[:2]+"/"+valid[2:] {
+		t.Errorf("Expected only the valid entry, got %d entries", len(entries))
+	}
+	if err := index.Validate(); err == nil {
+		t.Error("Expected an error for an index with invalid hashes")
+	}
+	index.Hashes["sha256"] = []string{"", valid}
+	if err := index.Validate(); err != nil {
+		t.Errorf("Expected no error for a valid index, got %v", err)
+	}
+}
+
+func TestFindEvictable(t *testing.T) {
+	viper.Set("cache.directory", t.TempDir())
+	t.Cleanup(func() {
+		viper.Set("cache.directory", nil)
+	})
+
+	cacheIndex, err := LoadCacheIndex()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var handles []*CacheIndexHandle
+	for i, content := range []string{"oldest", "middle", "newest"} {
+		hash := sha256.Sum256([]byte(content))
+		hashes := map[string]string{"sha256": hex.EncodeToString(hash[:])}
+		if _, err := cacheIndex.ImportFile(hashes, bytes.NewReader([]byte(content))); err != nil {
+			t.Fatal(err)
+		}
+		handle := cacheIndex.GetHandleFromHash("sha256", hashes["sha256"])
+		cacheIndex.AccessTimes[handle.hashIdx] = time.Now().Add(time.Duration(i-3) * 24 * time.Hour).Unix()
+		handles = append(handles, handle)
+	}
+
+	evictable, _, err := cacheIndex.FindEvictable(CacheEvictionPolicy{MaxAge: 36 * time.Hour})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(evictable) != 2 || evictable[0].hashIdx != handles[0].hashIdx || evictable[1].hashIdx != handles[1].hashIdx {
+		t.Errorf("Expected the two oldest entries to be evictable, got %d entries", len(evictable))
+	}
+
+	evictable, size, err := cacheIndex.FindEvictable(CacheEvictionPolicy{MaxSize: 6, Keep: handles[:1]})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(evictable) != 2 || evictable[0].hashIdx != handles[1].hashIdx || size != 12 {
+		t.Errorf("Expected all entries except the kept entry to be evictable, got %d entries (%d bytes)", len(evictable), size)
+	}
+
+	if err := evictable[0].Remove(); err != nil {
+		t.Fatal(err)
+	}
+	if cacheIndex.GetHandleFromHash("sha256", evictable[0].Hashes["sha256"]) != nil {
+		t.Error("Expected the removed entry to be removed from the cache index")
+	}
+}
+
+func TestRemoveIndices(t *testing.T) {
+	list := removeIndices([]string{"a", "b", "c", "d", "e"}, []int{1, 2, 4})
+	if !slices.Equal(list, []string{"a", "d"}) {
+		t.Errorf("Expected [a d], got %v", list)
+	}
+}
+
+func TestVerify(t *testing.T) {
+	viper.Set("cache.directory", t.TempDir())
+	t.Cleanup(func() {
+		viper.Set("cache.directory", nil)
+	})
+
+	cacheIndex, err := LoadCacheIndex()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var handles []*CacheIndexHandle
+	for _, content := range []string{"valid", "corrupt", "missing", "orphan"} {
+		hash := sha256.Sum256([]byte(content))
+		hashes := map[string]string{"sha256": hex.EncodeToString(hash[:]), "sha1": ""}
+		if _, err := cacheIndex.ImportFile(hashes, bytes.NewReader([]byte(content))); err != nil {
+			t.Fatal(err)
+		}
+		handles = append(handles, cacheIndex.GetHandleFromHash("sha256", hashes["sha256"]))
+	}
+	if err := os.WriteFile(handles[1].Path(), []byte("corrupted"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Remove(handles[2].Path()); err != nil {
+		t.Fatal(err)
+	}
+	// Remove the index entry of the orphaned file, leaving the file in place
+	handles[3].clearEntry()
+
+	result, err := cacheIndex.Verify()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Checked != 3 || len(result.Corrupt) != 1 || len(result.Missing) != 1 || len(result.Orphaned) != 1 {
+		t.Fatalf("Expected 3 checked, 1 corrupt, 1 missing and 1 orphaned file, got %d, %d, %d and %d",
+			result.Checked, len(result.Corrupt), len(result.Missing), len(result.Orphaned))
+	}
+	if result.Corrupt[0].hashIdx != handles[1].hashIdx || result.Missing[0].hashIdx != handles[2].hashIdx {
+		t.Error("Expected the corrupt and missing files to be found")
+	}
+
+	ok, err := cacheIndex.ImportOrphan(result.Orphaned[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ok || cacheIndex.GetHandleFromHash("sha256", handles[3].Hashes["sha256"]) == nil {
+		t.Error("Expected the orphaned file to be added back to the index")
+	}
+}
+
+func TestSaveMerge(t *testing.T) {
+	viper.Set("cache.directory", t.TempDir())
+	t.Cleanup(func() {
+		viper.Set("cache.directory", nil)
+	})
+
+	importFile := func(cacheIndex *CacheIndex, content string) string {
+		hash := sha256.Sum256([]byte(content))
+		hashes := map[string]string{"sha256": hex.EncodeToString(hash[:])}
+		if _, err := cacheIndex.ImportFile(hashes, bytes.NewReader([]byte(content))); err != nil {
+			t.Fatal(err)
+		}
+		return hashes["sha256"]
+	}
+
+	first, err := LoadCacheIndex()
+	if err != nil {
+		t.Fatal(err)
+	}
+	removedHash := importFile(first, "removed")
+	if err := first.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Two processes load the index at the same time, then each make changes
+	first, err = LoadCacheIndex()
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := LoadCacheIndex()
+	if err != nil {
+		t.Fatal(err)
+	}
+	firstHash := importFile(first, "first")
+	secondHash := importFile(second, "second")
+	if err := second.GetHandleFromHash("sha256", removedHash).Remove(); err != nil {
+		t.Fatal(err)
+	}
+	if err := second.Save(); err != nil {
+		t.Fatal(err)
+	}
+	if err := first.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	merged, err := LoadCacheIndex()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if merged.GetHandleFromHash("sha256", firstHash) == nil || merged.GetHandleFromHash("sha256", secondHash) == nil {
+		t.Error("Expected the entries added by both processes to be in the saved index")
+	}
+	if merged.GetHandleFromHash("sha256", removedHash) != nil {
+		t.Error("Expected the entry removed by the second process to be removed from the saved index")
+	}
+}
+
+func TestSaveMergeInFlightHandle(t *testing.T) {
+	viper.Set("cache.directory", t.TempDir())
+	t.Cleanup(func() {
+		viper.Set("cache.directory", nil)
+	})
+
+	first, err := LoadCacheIndex()
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := LoadCacheIndex()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The first process has a download in progress when the second process' changes are merged
+	inFlightContent := []byte("in flight")
+	inFlightHash := sha256.Sum256(inFlightContent)
+	handle, exists := first.NewHandleFromHashes(map[string]string{"sha256": hex.EncodeToString(inFlightHash[:])})
+	if exists {
+		t.Fatal("Expected a new handle")
+	}
+	otherHash := sha256.Sum256([]byte("other"))
+	if _, err := second.ImportFile(map[string]string{"sha256": hex.EncodeToString(otherHash[:])}, bytes.NewReader([]byte("other"))); err != nil {
+		t.Fatal(err)
+	}
+	if err := second.Save(); err != nil {
+		t.Fatal(err)
+	}
+	if err := first.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	// The entry added by the second process must not take the index of the in-flight handle
+	other := first.GetHandleFromHash("sha256", hex.EncodeToString(otherHash[:]))
+	if other == nil {
+		t.Fatal("Expected the entry added by the second process to be merged")
+	}
+	if other.hashIdx == handle.hashIdx {
+		t.Fatalf("Expected the merged entry not to reuse index %d of the in-flight handle", handle.hashIdx)
+	}
+	file, err := handle.CreateFromTemp(writeTempFile(t, inFlightContent))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = file.Close()
+	handle.UpdateIndex()
+	if err := first.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	merged, err := LoadCacheIndex()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, hash := range [][32]byte{inFlightHash, otherHash} {
+		if merged.GetHandleFromHash("sha256", hex.EncodeToString(hash[:])) == nil {
+			t.Errorf("Expected %x to be in the saved index", hash)
+		}
+	}
+}
+
+func writeTempFile(t *testing.T, content []byte) *os.File {
+	t.Helper()
+	f, err := os.CreateTemp(t.TempDir(), "tmp")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.Write(content); err != nil {
+		t.Fatal(err)
+	}
+	return f
+}
@@ -1,7 +1,6 @@
 package core
 
 import (
-	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
@@ -9,7 +8,10 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
+	"time"
 
+	"github.com/spf13/viper"
 	"slices"
 )
 
@@ -27,6 +29,9 @@ func GetWithUA(url string, contentType string) (resp *http.Response, err error)
 
 const DownloadCacheImportFolder = "import"
 
+// DefaultDownloadThreads is the number of files downloaded at once when download-threads is not set
+const DefaultDownloadThreads = 4
+
 type DownloadSession interface {
 	GetManualDownloads() []ManualDownload
 	StartDownloads() chan CompletedDownload
@@ -46,7 +51,7 @@ type CompletedDownload struct {
 }
 
 type downloadSessionInternal struct {
-	cacheIndex           CacheIndex
+	cacheIndex           *CacheIndex
 	cacheFolder          string
 	hashesToObtain       []string
 	manualDownloads      []ManualDownload
@@ -58,8 +63,10 @@ type downloadTask struct {
 	metaDownloaderData MetaDownloaderData
 	mod                *Mod
 	url                string
-	hashFormat         string
-	hash               string
+	// mirrors are tried in order when downloading from url fails
+	mirrors    []string
+	hashFormat string
+	hash       string
 }
 
 func (d *downloadSessionInternal) GetManualDownloads() []ManualDownload {
@@ -72,50 +79,70 @@ func (d *downloadSessionInternal) StartDownloads() chan CompletedDownload {
 		for _, found := range d.foundManualDownloads {
 			downloads <- found
 		}
-		for _, task := range d.downloadTasks {
-			warnings := make([]error, 0)
 
-			// Get handle for mod
-			cacheHandle := d.cacheIndex.GetHandleFromHash(task.hashFormat, task.hash)
-			if cacheHandle != nil {
-				download, err := reuseExistingFile(cacheHandle, d.hashesToObtain, task.mod)
-				if err != nil {
-					// Remove handle and try again
-					cacheHandle.Remove()
-					cacheHandle = nil
-					warnings = append(warnings, fmt.Errorf("redownloading cached file: %w", err))
-				} else {
-					downloads <- download
-					continue
-				}
-			}
-
-			download, err := downloadNewFile(&task, d.cacheFolder, d.hashesToObtain, &d.cacheIndex)
-			if err != nil {
-				downloads <- CompletedDownload{
-					Error: err,
-					Mod:   task.mod,
+		threads := viper.GetInt("download-threads")
+		if threads < 1 {
+			threads = DefaultDownloadThreads
+		}
+		tasks := make(chan *downloadTask)
+		var wg sync.WaitGroup
+		for i := 0; i < threads; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for task := range tasks {
+					downloads <- d.runTask(task)
 				}
-			} else {
-				download.Warnings = warnings
-				downloads <- download
-			}
+			}()
 		}
+		for i := range d.downloadTasks {
+			tasks <- &d.downloadTasks[i]
+		}
+		close(tasks)
+		wg.Wait()
 		close(downloads)
 	}()
 	return downloads
 }
 
-func (d *downloadSessionInternal) SaveIndex() error {
-	data, err := json.Marshal(d.cacheIndex)
-	if err != nil {
-		return fmt.Errorf("failed to serialise index: %w", err)
+func (d *downloadSessionInternal) runTask(task *downloadTask) CompletedDownload {
+	warnings := make([]error, 0)
+
+	// Get handle for mod
+	cacheHandle := d.cacheIndex.GetHandleFromHash(task.hashFormat, task.hash)
+	if cacheHandle != nil {
+		download, err := reuseExistingFile(cacheHandle, d.hashesToObtain, task.mod)
+		if err != nil {
+			// Remove handle and try again
+			_ = cacheHandle.Remove()
+			warnings = append(warnings, fmt.Errorf("redownloading cached file: %w", err))
+		} else {
+			return download
+		}
+	}
+
+	if task.url == "" && task.metaDownloaderData == nil {
+		// Files found in the cache in offline mode can't be downloaded again
+		return CompletedDownload{
+			Error:    errors.New("file is missing from the cache, and can't be downloaded in offline mode"),
+			Mod:      task.mod,
+			Warnings: warnings,
+		}
 	}
-	err = os.WriteFile(filepath.Join(d.cacheFolder, "index.json"), data, 0644)
+
+	download, err := downloadNewFile(task, d.cacheFolder, d.hashesToObtain, d.cacheIndex)
 	if err != nil {
-		return fmt.Errorf("failed to write index: %w", err)
+		return CompletedDownload{
+			Error: err,
+			Mod:   task.mod,
+		}
 	}
-	return nil
+	download.Warnings = warnings
+	return download
+}
+
+func (d *downloadSessionInternal) SaveIndex() error {
+	return d.cacheIndex.Save()
 }
 
 func reuseExistingFile(cacheHandle *CacheIndexHandle, hashesToObtain []string, mod *Mod) (CompletedDownload, error) {
@@ -138,6 +165,8 @@ func reuseExistingFile(cacheHandle *CacheIndexHandle, hashesToObtain []string, m
 			warnings = cacheHandle.UpdateIndex()
 		}
 
+		cacheHandle.Touch()
+
 		return CompletedDownload{
 			File:     file,
 			Mod:      mod,
@@ -155,36 +184,49 @@ func downloadNewFile(task *downloadTask, cacheFolder string, hashesToObtain []st
 	if err != nil {
 		return CompletedDownload{}, fmt.Errorf("failed to create temporary file for download: %w", err)
 	}
+	// Clean up the temporary file unless it is moved into the cache
+	moved := false
+	defer func() {
+		if !moved {
+			_ = tempFile.Close()
+			_ = os.Remove(tempFile.Name())
+		}
+	}()
 
 	hashesToObtain, hashes := getHashListsForDownload(hashesToObtain, task.hashFormat, task.hash)
-	var data io.ReadCloser
-	if task.url != "" {
-		resp, err := GetWithUA(task.url, "application/octet-stream")
+	if task.url != "" && !IsLocalURL(task.url) {
+		err = fetchFromURLs(append([]string{task.url}, task.mirrors...), tempFile, hashesToObtain, hashes)
 		if err != nil {
-			return CompletedDownload{}, fmt.Errorf("failed to download %s: %w", task.url, err)
-		}
-		if resp.StatusCode != 200 {
-			_ = resp.Body.Close()
-			return CompletedDownload{}, fmt.Errorf("failed to download %s: invalid status code %v", task.url, resp.StatusCode)
+			return CompletedDownload{}, err
 		}
-		data = resp.Body
 	} else {
-		data, err = task.metaDownloaderData.DownloadFile()
+		var data io.ReadCloser
+		if task.url != "" {
+			data, err = task.mod.OpenLocalFile()
+			if err != nil {
+				return CompletedDownload{}, fmt.Errorf("failed to read local file %s: %w", task.url, err)
+			}
+		} else {
+			data, err = task.metaDownloaderData.DownloadFile()
+			if err != nil {
+				return CompletedDownload{}, err
+			}
+		}
+
+		err = teeHashes(hashesToObtain, hashes, tempFile, data)
+		_ = data.Close()
 		if err != nil {
-			return CompletedDownload{}, err
+			return CompletedDownload{}, fmt.Errorf("failed to download: %w", err)
 		}
 	}
 
-	err = teeHashes(hashesToObtain, hashes, tempFile, data)
-	_ = data.Close()
-	if err != nil {
-		return CompletedDownload{}, fmt.Errorf("failed to download: %w", err)
-	}
+	// Only one download can be moved into the cache at a time, so that identical files downloaded at the same time
+	// don't both get a new entry in the index
+	index.fileMu.Lock()
+	defer index.fileMu.Unlock()
 
 	// Create handle with calculated hashes
 	cacheHandle, alreadyExists := index.NewHandleFromHashes(hashes)
-	// Update index stored hashes
-	warnings := cacheHandle.UpdateIndex()
 
 	var file *os.File
 	if alreadyExists {
@@ -200,10 +242,12 @@ func downloadNewFile(task *downloadTask, cacheFolder string, hashesToObtain []st
 		// Automatically closes tempFile
 		file, err = cacheHandle.CreateFromTemp(tempFile)
 		if err != nil {
-			_ = tempFile.Close()
 			return CompletedDownload{}, fmt.Errorf("failed to move file %s to cache: %w", cacheHandle.Path(), err)
 		}
+		moved = true
 	}
+	// Update index stored hashes, once the file is in the cache (so other downloads can't find it before it exists)
+	warnings := cacheHandle.UpdateIndex()
 
 	return CompletedDownload{
 		File:     file,
@@ -289,13 +333,22 @@ func teeHashes(hashesToObtain []string, hashes map[string]string,
 }
 
 const cacheHashFormat = "sha256"
-const cacheLatestVersion = 2
+const cacheLatestVersion = 3
 
 type CacheIndex struct {
-	Version     uint32
-	Hashes      map[string][]string
+	Version uint32
+	Hashes  map[string][]string
+	// AccessTimes stores the time (as a Unix timestamp) each entry was last used, in the same order as Hashes
+	AccessTimes []int64
 	cachePath   string
 	nextHashIdx int
+	// mu guards Hashes and nextHashIdx, as downloads update the index concurrently
+	mu sync.Mutex
+	// fileMu guards moving new files into the cache
+	fileMu sync.Mutex
+	// removed stores the hashes (in the cache hash format) of entries removed since the index was loaded, so they
+	// aren't added back when merging with the index saved by other processes
+	removed map[string]bool
 }
 
 type CacheIndexHandle struct {
@@ -312,6 +365,10 @@ func (c *CacheIndex) updateVersion() {
 		// in version 1 are broken.
 		toRemove := []int{}
 		for hashIdx, hash := range c.Hashes[cacheHashFormat] {
+			if hash == "" {
+				// Removed entries are cleaned up after loading the index
+				continue
+			}
 			stats, err := os.Stat(filepath.Join(c.cachePath, hash[:2], hash[2:]))
 			if err != nil {
 				// failed to open file? Remove it from the cache then
@@ -325,8 +382,23 @@ func (c *CacheIndex) updateVersion() {
 		for hashName := range c.Hashes {
 			c.Hashes[hashName] = removeIndices(c.Hashes[hashName], toRemove)
 		}
+		c.AccessTimes = removeIndices(c.AccessTimes, toRemove)
 		c.Version = 2
 	}
+	if c.Version == 2 {
+		// Version 3 added access times; use the modification times of existing files, as the closest approximation
+		c.AccessTimes = make([]int64, len(c.Hashes[cacheHashFormat]))
+		for hashIdx, hash := range c.Hashes[cacheHashFormat] {
+			if hash == "" {
+				continue
+			}
+			stats, err := os.Stat(filepath.Join(c.cachePath, hash[:2], hash[2:]))
+			if err == nil {
+				c.AccessTimes[hashIdx] = stats.ModTime().Unix()
+			}
+		}
+		c.Version = 3
+	}
 }
 
 func (c *CacheIndex) getHashesMap(i int) map[string]string {
@@ -340,6 +412,12 @@ func (c *CacheIndex) getHashesMap(i int) map[string]string {
 }
 
 func (c *CacheIndex) GetHandleFromHash(hashFormat string, hash string) *CacheIndexHandle {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.getHandleFromHash(hashFormat, hash)
+}
+
+func (c *CacheIndex) getHandleFromHash(hashFormat string, hash string) *CacheIndexHandle {
 	storedHashFmtList, hasStoredHashFmt := c.Hashes[hashFormat]
 	if hasStoredHashFmt {
 		hashIdx := slices.Index(storedHashFmtList, strings.ToLower(hash))
@@ -358,6 +436,8 @@ func (c *CacheIndex) GetHandleFromHash(hashFormat string, hash string) *CacheInd
 // obtain the necessary hash. Only use this for manually downloaded files, as it can rehash every file in the cache, which
 // can be more time-consuming than just redownloading the file and noticing it is already in the index!
 func (c *CacheIndex) GetHandleFromHashForce(hashFormat string, hash string) (*CacheIndexHandle, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
 	storedHashFmtList, hasStoredHashFmt := c.Hashes[hashFormat]
 	if hasStoredHashFmt {
 		// Ensure hash list is extended to the length of the cache hash format list
@@ -371,7 +451,7 @@ func (c *CacheIndex) GetHandleFromHashForce(hashFormat string, hash string) (*Ca
 					hashIdx: hashIdx,
 					Hashes:  c.getHashesMap(hashIdx),
 				}, nil
-			} else if curHash == "" {
+			} else if curHash == "" && c.Hashes[cacheHashFormat][hashIdx] != "" {
 				var err error
 				storedHashFmtList[hashIdx], err = c.rehashFile(c.Hashes[cacheHashFormat][hashIdx], hashFormat)
 				if err != nil {
@@ -391,6 +471,9 @@ func (c *CacheIndex) GetHandleFromHashForce(hashFormat string, hash string) (*Ca
 		storedHashFmtList = make([]string, len(c.Hashes[cacheHashFormat]))
 		c.Hashes[hashFormat] = storedHashFmtList
 		for hashIdx, cacheHash := range c.Hashes[cacheHashFormat] {
+			if cacheHash == "" {
+				continue
+			}
 			var err error
 			storedHashFmtList[hashIdx], err = c.rehashFile(cacheHash, hashFormat)
 			if err != nil {
@@ -441,8 +524,10 @@ func (c *CacheIndex) NewHandleFromHashes(hashes map[string]string) (*CacheIndexH
 	if _, ok := hashes[cacheHashFormat]; !ok {
 		panic("NewHandleFromHashes didn't get any value for " + cacheHashFormat)
 	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
 	// Only compare with the cache hash format - other hashes might be insecure or likely to collide
-	handle := c.GetHandleFromHash(cacheHashFormat, hashes[cacheHashFormat])
+	handle := c.getHandleFromHash(cacheHashFormat, hashes[cacheHashFormat])
 	if handle != nil {
 		// Add hashes to handle
 		for hashFormat2, hash2 := range hashes {
@@ -549,6 +634,9 @@ func (h *CacheIndexHandle) CreateFromTemp(temp *os.File) (*os.File, error) {
 }
 
 func (h *CacheIndexHandle) UpdateIndex() (warnings []error) {
+	h.index.mu.Lock()
+	defer h.index.mu.Unlock()
+	h.setAccessTime()
 	// Add hashes to index
 	for hashFormat, hash := range h.Hashes {
 		hashList := h.index.Hashes[hashFormat]
@@ -571,27 +659,60 @@ func (h *CacheIndexHandle) UpdateIndex() (warnings []error) {
 	return
 }
 
-func (h *CacheIndexHandle) Remove() {
+// Touch records that this handle's file has been used, for removing the least recently used files from the cache
+func (h *CacheIndexHandle) Touch() {
+	h.index.mu.Lock()
+	defer h.index.mu.Unlock()
+	h.setAccessTime()
+}
+
+func (h *CacheIndexHandle) setAccessTime() {
+	if h.hashIdx >= len(h.index.AccessTimes) {
+		h.index.AccessTimes = append(h.index.AccessTimes, make([]int64, (h.hashIdx-len(h.index.AccessTimes))+1)...)
+	}
+	h.index.AccessTimes[h.hashIdx] = time.Now().Unix()
+}
+
+// Remove clears this handle's entry in the index and deletes its file; the entry is left empty rather than deleted so
+// that the indexes of other handles stay valid, and is cleaned up the next time the index is loaded
+func (h *CacheIndexHandle) Remove() error {
+	// Prevent a download of the same file from being moved into the cache while it is removed
+	h.index.fileMu.Lock()
+	defer h.index.fileMu.Unlock()
+	path := h.Path()
+	h.clearEntry()
+	err := os.Remove(path)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to delete %s: %w", path, err)
+	}
+	return nil
+}
+
+func (h *CacheIndexHandle) clearEntry() {
+	h.index.mu.Lock()
+	defer h.index.mu.Unlock()
+	if h.index.removed != nil {
+		h.index.removed[h.Hashes[cacheHashFormat]] = true
+	}
 	for hashFormat := range h.Hashes {
 		hashList := h.index.Hashes[hashFormat]
 		if h.hashIdx < len(hashList) {
-			h.index.Hashes[hashFormat] = slices.Delete(hashList, h.hashIdx, h.hashIdx+1)
+			hashList[h.hashIdx] = ""
 		}
 	}
-	return
 }
 
-func removeIndices(hashList []string, indices []int) []string {
+func removeIndices[T any](list []T, indices []int) []T {
 	i := 0
-	for _, v := range hashList {
-		if len(indices) > 0 && i == indices[0] {
+	for j, v := range list {
+		if len(indices) > 0 && j == indices[0] {
 			indices = indices[1:]
 		} else {
-			hashList[i] = v
+			list[i] = v
 			i++
 		}
 	}
-	return hashList[:i]
+	return list[:i]
 }
 
 func removeEmpty(hashList []string) ([]string, []int) {
@@ -608,9 +729,9 @@ func removeEmpty(hashList []string) ([]string, []int) {
 	return hashList[:i], indices
 }
 
-func CreateDownloadSession(mods []*Mod, hashesToObtain []string) (DownloadSession, error) {
-	// Load cache index
-	cacheIndex := CacheIndex{Version: cacheLatestVersion, Hashes: make(map[string][]string)}
+// LoadCacheIndex loads the index of the download cache, creating the cache folder if it doesn't exist and moving any
+// files in the import folder into the cache
+func LoadCacheIndex() (*CacheIndex, error) {
 	cachePath, err := GetPackwizCache()
 	if err != nil {
 		return nil, fmt.Errorf("failed to load cache: %w", err)
@@ -623,29 +744,16 @@ func CreateDownloadSession(mods []*Mod, hashesToObtain []string) (DownloadSessio
 	if err != nil {
 		return nil, fmt.Errorf("failed to create cache temp directory: %w", err)
 	}
-	cacheIndexData, err := os.ReadFile(filepath.Join(cachePath, "index.json"))
+	// Other packwiz processes using the same cache can't change it while it is loaded
+	unlock, err := lockCache(cachePath)
 	if err != nil {
-		if !os.IsNotExist(err) {
-			return nil, fmt.Errorf("failed to read cache index file: %w", err)
-		}
-	} else {
-		err = json.Unmarshal(cacheIndexData, &cacheIndex)
-		if err != nil {
-			return nil, fmt.Errorf("failed to read cache index file: %w", err)
-		}
-	}
-
-	// Ensure some parts of the index are initialised
-	_, hasCacheHashFmt := cacheIndex.Hashes[cacheHashFormat]
-	if !hasCacheHashFmt {
-		cacheIndex.Hashes[cacheHashFormat] = make([]string, 0)
+		return nil, err
 	}
-	cacheIndex.cachePath = cachePath
+	defer unlock()
 
-	// Ensure the cache's version is up-to-date
-	cacheIndex.updateVersion()
-	if cacheIndex.Version > cacheLatestVersion {
-		return nil, fmt.Errorf("cache index is too new (version %v)", cacheIndex.Version)
+	cacheIndex, err := readCacheIndex(cachePath)
+	if err != nil {
+		return nil, err
 	}
 
 	// Clean up empty entries in index
@@ -657,9 +765,11 @@ func CreateDownloadSession(mods []*Mod, hashesToObtain []string) (DownloadSessio
 				cacheIndex.Hashes[hashFormat] = removeIndices(v, removedEntries)
 			}
 		}
+		cacheIndex.AccessTimes = removeIndices(cacheIndex.AccessTimes, removedEntries)
 	}
 
 	cacheIndex.nextHashIdx = len(cacheIndex.Hashes[cacheHashFormat])
+	cacheIndex.removed = make(map[string]bool)
 
 	// Create import folder
 	err = os.MkdirAll(filepath.Join(cachePath, DownloadCacheImportFolder), 0755)
@@ -671,6 +781,15 @@ func CreateDownloadSession(mods []*Mod, hashesToObtain []string) (DownloadSessio
 	if err != nil {
 		return nil, fmt.Errorf("error updating cache import folder: %w", err)
 	}
+	return cacheIndex, nil
+}
+
+func CreateDownloadSession(mods []*Mod, hashesToObtain []string) (DownloadSession, error) {
+	cacheIndex, err := LoadCacheIndex()
+	if err != nil {
+		return nil, err
+	}
+	cachePath := cacheIndex.cachePath
 
 	// Create session
 	downloadSession := downloadSessionInternal{
@@ -680,13 +799,32 @@ func CreateDownloadSession(mods []*Mod, hashesToObtain []string) (DownloadSessio
 	}
 
 	pendingMetadata := make(map[string][]*Mod)
+	offline := viper.GetBool("offline")
+	var missingFiles []string
 
 	// Get necessary metadata for all files
 	for _, mod := range mods {
-		if mod.Download.Mode == ModeURL || mod.Download.Mode == "" {
+		isURLMode := mod.Download.Mode == ModeURL || mod.Download.Mode == ""
+		if offline && !(isURLMode && IsLocalURL(mod.Download.URL)) {
+			// In offline mode, files can only be used from the cache (or the pack's local store)
+			handle, err := cacheIndex.GetHandleFromHashForce(mod.Download.HashFormat, mod.Download.Hash)
+			if err != nil {
+				return nil, fmt.Errorf("failed to lookup %s in cache: %w", mod.Name, err)
+			}
+			if handle == nil {
+				missingFiles = append(missingFiles, fmt.Sprintf("%s (%s)", mod.Name, mod.FileName))
+				continue
+			}
+			downloadSession.downloadTasks = append(downloadSession.downloadTasks, downloadTask{
+				mod:        mod,
+				hashFormat: mod.Download.HashFormat,
+				hash:       mod.Download.Hash,
+			})
+		} else if isURLMode {
 			downloadSession.downloadTasks = append(downloadSession.downloadTasks, downloadTask{
 				mod:        mod,
 				url:        mod.Download.URL,
+				mirrors:    mod.Download.Mirrors,
 				hashFormat: mod.Download.HashFormat,
 				hash:       mod.Download.Hash,
 			})
@@ -697,6 +835,10 @@ func CreateDownloadSession(mods []*Mod, hashesToObtain []string) (DownloadSessio
 			return nil, fmt.Errorf("unknown download mode %s for %s", mod.Download.Mode, mod.Name)
 		}
 	}
+	if len(missingFiles) > 0 {
+		return nil, fmt.Errorf("offline mode is enabled, but %d file(s) are missing from the cache:\n%s",
+			len(missingFiles), strings.Join(missingFiles, "\n"))
+	}
 
 	for dlID, mods := range pendingMetadata {
 		downloader, ok := MetaDownloaders[dlID]
@@ -719,6 +861,7 @@ func CreateDownloadSession(mods []*Mod, hashesToObtain []string) (DownloadSessio
 					if err != nil {
 						return nil, fmt.Errorf("failed to open manual download %s: %w", v.Name, err)
 					}
+					handle.Touch()
 					downloadSession.foundManualDownloads = append(downloadSession.foundManualDownloads, CompletedDownload{
 						File:   file,
 						Mod:    v,
@@ -728,18 +871,21 @@ func CreateDownloadSession(mods []*Mod, hashesToObtain []string) (DownloadSessio
 					downloadSession.manualDownloads = append(downloadSession.manualDownloads, manualDownload)
 				}
 			} else {
-				downloadSession.downloadTasks = append(downloadSession.downloadTasks, downloadTask{
+				task := downloadTask{
 					mod:                v,
 					metaDownloaderData: meta[i],
 					hashFormat:         v.Download.HashFormat,
 					hash:               v.Download.Hash,
-				})
+				}
+				// Files with a URL can be downloaded directly, so the download can be retried and resumed
+				if urlData, ok := meta[i].(MetaDownloaderURLData); ok {
+					task.url = urlData.GetDownloadURL()
+				}
+				downloadSession.downloadTasks = append(downloadSession.downloadTasks, task)
 			}
 		}
 	}
 
-	// TODO: index housekeeping? i.e. remove deleted files, remove old files (LRU?)
-
 	// Save index after importing and Force index updates
 	err = downloadSession.SaveIndex()
 	if err != nil {
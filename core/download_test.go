@@ -0,0 +1,257 @@
+package core
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/spf13/viper"
+)
+
+func TestParallelDownloads(t *testing.T) {
+	httpmock.Activate(t)
+	viper.Set("cache.directory", t.TempDir())
+	viper.Set("download-threads", 4)
+	t.Cleanup(func() {
+		viper.Set("cache.directory", nil)
+		viper.Set("download-threads", nil)
+	})
+
+	var mods []*Mod
+	for i := 0; i < 20; i++ {
+		// Every pair of mods shares the same file, so the same file is downloaded concurrently
+		content := []byte(fmt.Sprintf("file %d", i/2))
+		hash := sha256.Sum256(content)
+		url := fmt.Sprintf("https://example.com/%d.jar", i)
+		httpmock.RegisterResponder("GET", url, httpmock.NewBytesResponder(200, content))
+		mods = append(mods, &Mod{
+			Name: fmt.Sprintf("Mod %d", i),
+			Download: ModDownload{
+				URL:        url,
+				HashFormat: "sha256",
+				Hash:       hex.EncodeToString(hash[:]),
+			},
+		})
+	}
+
+	session, err := CreateDownloadSession(mods, []string{"sha1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	completed := make(map[*Mod]bool)
+	for dl := range session.StartDownloads() {
+		if dl.Error != nil {
+			t.Errorf("Download of %s failed: %s", dl.Mod.Name, dl.Error)
+			continue
+		}
+		_ = dl.File.Close()
+		if dl.Hashes["sha1"] == "" {
+			t.Errorf("Missing sha1 hash for %s", dl.Mod.Name)
+		}
+		completed[dl.Mod] = true
+	}
+	if len(completed) != len(mods) {
+		t.Errorf("Expected %d completed downloads, got %d", len(mods), len(completed))
+	}
+
+	index := session.(*downloadSessionInternal).cacheIndex
+	if entries := len(index.Hashes[cacheHashFormat]); entries != len(mods)/2 {
+		t.Errorf("Expected %d cache index entries, got %d", len(mods)/2, entries)
+	}
+}
+
+func TestLocalDownload(t *testing.T) {
+	viper.Set("cache.directory", t.TempDir())
+	t.Cleanup(func() {
+		viper.Set("cache.directory", nil)
+	})
+
+	packDir := t.TempDir()
+	content := []byte("local file")
+	hash := sha256.Sum256(content)
+	storeFile := filepath.Join(packDir, DefaultLocalStoreFolder, hex.EncodeToString(hash[:]), "local file.jar")
+	if err := os.MkdirAll(filepath.Dir(storeFile), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(storeFile, content, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	mod := &Mod{
+		Name: "Local",
+		Download: ModDownload{
+			HashFormat: "sha256",
+			Hash:       hex.EncodeToString(hash[:]),
+		},
+	}
+	metaFile := mod.SetMetaPath(filepath.Join(packDir, "mods", "local.pw.toml"))
+	url, err := GetLocalURL(metaFile, storeFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !IsLocalURL(url) {
+		t.Fatalf("Expected %s to be a local URL", url)
+	}
+	mod.Download.URL = url
+
+	session, err := CreateDownloadSession([]*Mod{mod}, []string{"sha1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for dl := range session.StartDownloads() {
+		if dl.Error != nil {
+			t.Fatalf("Download of %s failed: %s", dl.Mod.Name, dl.Error)
+		}
+		_ = dl.File.Close()
+		if dl.Hashes["sha1"] == "" {
+			t.Errorf("Missing sha1 hash for %s", dl.Mod.Name)
+		}
+	}
+}
+
+func TestOfflineDownload(t *testing.T) {
+	httpmock.Activate(t)
+	viper.Set("cache.directory", t.TempDir())
+	t.Cleanup(func() {
+		viper.Set("cache.directory", nil)
+		viper.Set("offline", nil)
+	})
+
+	newMod := func(name string, content []byte) *Mod {
+		hash := sha256.Sum256(content)
+		url := "https://example.com/" + name + ".jar"
+		httpmock.RegisterResponder("GET", url, httpmock.NewBytesResponder(200, content))
+		return &Mod{
+			Name:     name,
+			FileName: name + ".jar",
+			Download: ModDownload{
+				URL:        url,
+				HashFormat: "sha256",
+				Hash:       hex.EncodeToString(hash[:]),
+			},
+		}
+	}
+	cached := newMod("cached", []byte("cached file"))
+	uncached := newMod("uncached", []byte("uncached file"))
+
+	// Download the first file into the cache
+	session, err := CreateDownloadSession([]*Mod{cached}, []string{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for dl := range session.StartDownloads() {
+		if dl.Error != nil {
+			t.Fatalf("Download of %s failed: %s", dl.Mod.Name, dl.Error)
+		}
+		_ = dl.File.Close()
+	}
+	if err := session.SaveIndex(); err != nil {
+		t.Fatal(err)
+	}
+	httpmock.ZeroCallCounters()
+
+	viper.Set("offline", true)
+	_, err = CreateDownloadSession([]*Mod{cached, uncached}, []string{})
+	if err == nil || !strings.Contains(err.Error(), "uncached.jar") {
+		t.Fatalf("Expected an error listing the file missing from the cache, got %v", err)
+	}
+
+	session, err = CreateDownloadSession([]*Mod{cached}, []string{"sha1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for dl := range session.StartDownloads() {
+		if dl.Error != nil {
+			t.Fatalf("Download of %s failed: %s", dl.Mod.Name, dl.Error)
+		}
+		_ = dl.File.Close()
+	}
+	if calls := httpmock.GetTotalCallCount(); calls != 0 {
+		t.Errorf("Expected no requests in offline mode, got %d", calls)
+	}
+}
+
+type failingReader struct{}
+
+func (failingReader) Read([]byte) (int, error) {
+	return 0, errors.New("connection reset")
+}
+
+func TestDownloadRetries(t *testing.T) {
+	httpmock.Activate(t)
+	viper.Set("cache.directory", t.TempDir())
+	delay := downloadRetryDelay
+	downloadRetryDelay = time.Millisecond
+	t.Cleanup(func() {
+		viper.Set("cache.directory", nil)
+		downloadRetryDelay = delay
+	})
+
+	content := []byte("resumable file contents")
+	hash := sha256.Sum256(content)
+	// The first request fails part way through, so the second should resume from where it stopped
+	var ranges []string
+	httpmock.RegisterResponder("GET", "https://example.com/resume.jar", func(req *http.Request) (*http.Response, error) {
+		ranges = append(ranges, req.Header.Get("Range"))
+		if len(ranges) == 1 {
+			return &http.Response{
+				StatusCode: 200,
+				Body:       io.NopCloser(io.MultiReader(bytes.NewReader(content[:10]), failingReader{})),
+			}, nil
+		}
+		resp := httpmock.NewBytesResponse(206, content[10:])
+		resp.Header.Set("Content-Range", fmt.Sprintf("bytes 10-%d/%d", len(content)-1, len(content)))
+		return resp, nil
+	})
+
+	mirrorContent := []byte("mirrored file contents")
+	mirrorHash := sha256.Sum256(mirrorContent)
+	httpmock.RegisterResponder("GET", "https://example.com/missing.jar", httpmock.NewStringResponder(404, ""))
+	httpmock.RegisterResponder("GET", "https://mirror.example.com/broken.jar", httpmock.NewStringResponder(200, "wrong contents"))
+	httpmock.RegisterResponder("GET", "https://mirror.example.com/mirrored.jar", httpmock.NewBytesResponder(200, mirrorContent))
+
+	mods := []*Mod{{
+		Name: "Resume",
+		Download: ModDownload{
+			URL:        "https://example.com/resume.jar",
+			HashFormat: "sha256",
+			Hash:       hex.EncodeToString(hash[:]),
+		},
+	}, {
+		Name: "Mirrored",
+		Download: ModDownload{
+			URL:        "https://example.com/missing.jar",
+			Mirrors:    []string{"https://mirror.example.com/broken.jar", "https://mirror.example.com/mirrored.jar"},
+			HashFormat: "sha256",
+			Hash:       hex.EncodeToString(mirrorHash[:]),
+		},
+	}}
+
+	session, err := CreateDownloadSession(mods, []string{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for dl := range session.StartDownloads() {
+		if dl.Error != nil {
+			t.Fatalf("Download of %s failed: %s", dl.Mod.Name, dl.Error)
+		}
+		_ = dl.File.Close()
+	}
+	if len(ranges) != 2 || ranges[0] != "" || ranges[1] != "bytes=10-" {
+		t.Errorf("Expected the second request to resume the download, got ranges %q", ranges)
+	}
+	// Not found errors shouldn't be retried
+	if calls := httpmock.GetCallCountInfo()["GET https://example.com/missing.jar"]; calls != 1 {
+		t.Errorf("Expected 1 request to the missing URL, got %d", calls)
+	}
+}
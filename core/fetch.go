@@ -0,0 +1,191 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// DefaultDownloadRetries is the number of times a failed download is retried from each URL, when download-retries is
+// not set
+const DefaultDownloadRetries = 3
+
+// DefaultDownloadTimeout is how long a download can go without receiving any data before it is retried, when
+// download-timeout is not set
+const DefaultDownloadTimeout = time.Minute
+
+// downloadRetryDelay is the delay before the first retry of a failed download, which doubles after each retry up to
+// maxDownloadRetryDelay
+var downloadRetryDelay = time.Second
+
+const maxDownloadRetryDelay = 30 * time.Second
+
+func getDownloadRetries() int {
+	if !viper.IsSet("download-retries") {
+		return DefaultDownloadRetries
+	}
+	return max(viper.GetInt("download-retries"), 0)
+}
+
+func getDownloadTimeout() time.Duration {
+	if !viper.IsSet("download-timeout") {
+		return DefaultDownloadTimeout
+	}
+	return viper.GetDuration("download-timeout")
+}
+
+// fetchFromURLs downloads a file into dst, trying each URL in order until the download succeeds and the file matches
+// the expected hash. The hashes map is populated with the hashes in hashesToObtain.
+func fetchFromURLs(urls []string, dst *os.File, hashesToObtain []string, hashes map[string]string) error {
+	var errs []error
+	for _, u := range urls {
+		err := fetchURL(u, dst)
+		if err == nil {
+			_, err = dst.Seek(0, io.SeekStart)
+			if err != nil {
+				return err
+			}
+			err = teeHashes(hashesToObtain, hashes, io.Discard, dst)
+			if err == nil {
+				return nil
+			}
+		}
+		errs = append(errs, fmt.Errorf("failed to download %s: %w", u, err))
+		// Start from scratch for the next URL, as the partial file could be from a different (broken) server
+		err = resetFile(dst)
+		if err != nil {
+			return err
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// fetchURL downloads a file into dst, retrying with exponential backoff when the download fails with an error that
+// might be temporary. When dst already has data, the download is resumed from the end of it if the server supports it.
+func fetchURL(u string, dst *os.File) error {
+	retries := getDownloadRetries()
+	timeout := getDownloadTimeout()
+	delay := downloadRetryDelay
+	for attempt := 0; ; attempt++ {
+		retry, err := fetchAttempt(u, dst, timeout)
+		if err == nil || !retry || attempt >= retries {
+			return err
+		}
+		time.Sleep(delay)
+		delay = min(delay*2, maxDownloadRetryDelay)
+	}
+}
+
+// fetchAttempt makes a single request to download (the rest of) a file into dst, returning whether the download should
+// be retried if it failed
+func fetchAttempt(u string, dst *os.File, timeout time.Duration) (bool, error) {
+	offset, err := dst.Seek(0, io.SeekEnd)
+	if err != nil {
+		return false, err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var timer *time.Timer
+	if timeout > 0 {
+		// The request is cancelled if no data is received for the timeout
+		timer = time.AfterFunc(timeout, cancel)
+		defer timer.Stop()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", u, nil)
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("User-Agent", UserAgent)
+	req.Header.Set("Accept", "application/octet-stream")
+	if offset > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return true, timeoutError(ctx, err, timeout)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusOK:
+		// The server sent the whole file, either because it doesn't support ranges or nothing was downloaded yet
+		if offset > 0 {
+			err = resetFile(dst)
+			if err != nil {
+				return false, err
+			}
+		}
+	case resp.StatusCode == http.StatusPartialContent && offset > 0:
+		var start int64
+		_, err = fmt.Sscanf(resp.Header.Get("Content-Range"), "bytes %d-", &start)
+		if err != nil || start != offset {
+			err = resetFile(dst)
+			if err != nil {
+				return false, err
+			}
+			return true, fmt.Errorf("invalid Content-Range %q", resp.Header.Get("Content-Range"))
+		}
+	case resp.StatusCode == http.StatusRequestedRangeNotSatisfiable && offset > 0:
+		err = resetFile(dst)
+		if err != nil {
+			return false, err
+		}
+		return true, errors.New("server could not resume the download")
+	default:
+		return isRetryableStatus(resp.StatusCode), fmt.Errorf("invalid status code %v", resp.StatusCode)
+	}
+
+	var body io.Reader = resp.Body
+	if timer != nil {
+		body = &timeoutReader{r: resp.Body, timer: timer, timeout: timeout}
+	}
+	_, err = io.Copy(dst, body)
+	if err != nil {
+		return true, timeoutError(ctx, err, timeout)
+	}
+	return false, nil
+}
+
+// isRetryableStatus returns true if a request failing with the given status code might succeed when retried
+func isRetryableStatus(code int) bool {
+	return code >= 500 || code == http.StatusRequestTimeout || code == http.StatusTooManyRequests
+}
+
+func timeoutError(ctx context.Context, err error, timeout time.Duration) error {
+	if ctx.Err() != nil {
+		return fmt.Errorf("no data received for %v", timeout)
+	}
+	return err
+}
+
+func resetFile(f *os.File) error {
+	err := f.Truncate(0)
+	if err != nil {
+		return err
+	}
+	_, err = f.Seek(0, io.SeekStart)
+	return err
+}
+
+// timeoutReader resets the timeout timer whenever data is read
+type timeoutReader struct {
+	r       io.Reader
+	timer   *time.Timer
+	timeout time.Duration
+}
+
+func (t *timeoutReader) Read(p []byte) (int, error) {
+	n, err := t.r.Read(p)
+	if n > 0 {
+		t.timer.Reset(t.timeout)
+	}
+	return n, err
+}
@@ -7,6 +7,7 @@ import (
 	"os"
 	"path"
 	"path/filepath"
+	"runtime"
 	"strings"
 	"time"
 
@@ -75,36 +76,34 @@ func (in *Index) updateFileHashGiven(path, format, hash string, markAsMetaFile b
 	return nil
 }
 
-// updateFile calculates the hash for a given path and updates it in the index
-func (in *Index) updateFile(path string) error {
-	var hashString string
-	if viper.GetBool("no-internal-hashes") {
-		hashString = ""
-	} else {
-		f, err := os.Open(path)
-		if err != nil {
-			return err
-		}
+// hashFile calculates the hash of a file to be stored in the index
+func hashFile(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
 
-		// Hash usage strategy (may change):
-		// Just use SHA256, overwrite existing hash regardless of what it is
-		// May update later to continue using the same hash that was already being used
-		h, err := GetHashImpl("sha256")
-		if err != nil {
-			_ = f.Close()
-			return err
-		}
-		if _, err := io.Copy(h, f); err != nil {
-			_ = f.Close()
-			return err
-		}
-		err = f.Close()
-		if err != nil {
-			return err
-		}
-		hashString = h.HashToString(h.Sum(nil))
+	// Hash usage strategy (may change):
+	// Just use SHA256, overwrite existing hash regardless of what it is
+	// May update later to continue using the same hash that was already being used
+	h, err := GetHashImpl("sha256")
+	if err != nil {
+		_ = f.Close()
+		return "", err
+	}
+	if _, err := io.Copy(h, f); err != nil {
+		_ = f.Close()
+		return "", err
 	}
+	err = f.Close()
+	if err != nil {
+		return "", err
+	}
+	return h.HashToString(h.Sum(nil)), nil
+}
 
+// updateFileHashed updates a file in the index given its hash, marking it as a metafile based on its extension
+func (in *Index) updateFileHashed(path string, hashString string) error {
 	markAsMetaFile := false
 	// If the file has an extension of pw.toml, set markAsMetaFile to true
 	if strings.HasSuffix(filepath.Base(path), MetaExtension) {
@@ -166,9 +165,6 @@ func readGitignore(path string) (*gitignore.GitIgnore, bool) {
 
 // Refresh updates the hashes of all the files in the index, and adds new files to the index
 func (in *Index) Refresh() error {
-	// TODO: If needed, multithreaded hashing
-	// for i := 0; i < runtime.NumCPU(); i++ {}
-
 	// Is case-sensitivity a problem?
 	pathPF, _ := filepath.Abs(viper.GetString("pack-file"))
 	pathIndex, _ := filepath.Abs(in.indexFile)
@@ -193,6 +189,10 @@ func (in *Index) Refresh() error {
 			if ignore.MatchesPath(path) {
 				return fs.SkipDir
 			}
+			// Local files are referenced by metadata files, so they aren't included in the index
+			if IsInLocalStore(path) {
+				return fs.SkipDir
+			}
 			// Don't add directories to the file list
 			return nil
 		}
@@ -234,15 +234,68 @@ func (in *Index) Refresh() error {
 		),
 	)
 
-	for _, v := range fileList {
-		start := time.Now()
+	noInternalHashes := viper.GetBool("no-internal-hashes")
+	var cache *refreshCache
+	if !noInternalHashes {
+		cache = loadRefreshCache(pathIndex)
+	}
 
-		err := in.updateFile(v)
+	// Hash files on multiple threads; the index is only updated from this goroutine
+	type hashResult struct {
+		path     string
+		hash     string
+		entry    refreshCacheEntry
+		err      error
+		duration time.Duration
+	}
+	paths := make(chan string)
+	results := make(chan hashResult)
+	for i := 0; i < runtime.NumCPU(); i++ {
+		go func() {
+			for p := range paths {
+				start := time.Now()
+				res := hashResult{path: p}
+				if !noInternalHashes {
+					res.hash, res.entry, res.err = cache.hashFile(p)
+				}
+				res.duration = time.Since(start)
+				results <- res
+			}
+		}()
+	}
+	go func() {
+		for _, v := range fileList {
+			paths <- v
+		}
+		close(paths)
+	}()
+
+	var firstErr error
+	for range fileList {
+		res := <-results
+		// Keep receiving results after an error, so the workers can exit
+		if firstErr != nil {
+			continue
+		}
+		if res.err != nil {
+			firstErr = res.err
+			continue
+		}
+		err := in.updateFileHashed(res.path, res.hash)
 		if err != nil {
-			return err
+			firstErr = err
+			continue
 		}
-
-		progress.Increment(time.Since(start))
+		if cache != nil {
+			cache.update(res.path, res.entry)
+		}
+		progress.Increment(res.duration)
+	}
+	if firstErr != nil {
+		return firstErr
+	}
+	if cache != nil {
+		cache.save()
 	}
 	// Close bar
 	progress.SetTotal(int64(len(fileList)), true) // If len = 0, we have to manually set complete to true
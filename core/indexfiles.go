@@ -16,6 +16,8 @@ type IndexPathHolder interface {
 	markMetaFile()
 	markedFound() bool
 	IsMetaFile() bool
+	// GetHash returns the hash format and hash of the file (all aliases of a path share the same hash)
+	GetHash() (string, string)
 }
 
 // indexFile is a file in the index
@@ -51,6 +53,10 @@ func (i *indexFile) IsMetaFile() bool {
 	return i.MetaFile
 }
 
+func (i *indexFile) GetHash() (string, string) {
+	return i.HashFormat, i.Hash
+}
+
 type indexFileMultipleAlias map[string]indexFile
 
 func (i *indexFileMultipleAlias) updateHash(hash string, format string) {
@@ -89,6 +95,13 @@ func (i *indexFileMultipleAlias) IsMetaFile() bool {
 	panic("No entries in indexFileMultipleAlias")
 }
 
+func (i *indexFileMultipleAlias) GetHash() (string, string) {
+	for _, v := range *i {
+		return v.HashFormat, v.Hash
+	}
+	panic("No entries in indexFileMultipleAlias")
+}
+
 // updateFileEntry updates the hash of a file and marks as found; adding it if it doesn't exist
 // This also sets metafile if markAsMetaFile is set
 // This updates all existing aliassed variants of a file, but doesn't create new ones
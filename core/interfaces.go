@@ -25,6 +25,8 @@ type UpdateCheck struct {
 	// UpdateString is a string that details the update in some way to the user. Usually this will be in the form of
 	// a version change (1.0.0 -> 1.0.1), or a file name change (thanos-skin-1.0.0.jar -> thanos-skin-1.0.1.jar).
 	UpdateString string
+	// NewFileName is the file name of the updated file, if it is known before the update is carried out
+	NewFileName string
 	// CachedState can be used to preserve per-mod state between CheckUpdate and DoUpdate (e.g. file metadata)
 	CachedState interface{}
 	// Error stores an error for this specific mod
@@ -33,6 +35,97 @@ type UpdateCheck struct {
 	Error error
 }
 
+// IdentifiableUpdateData can optionally be implemented by the parsed update data returned from Updater.ParseUpdate,
+// to identify the project and version that a mod file is from (e.g. for matching mods between versions of a pack)
+type IdentifiableUpdateData interface {
+	// GetProjectID returns an ID that is unique to the project on this update system
+	GetProjectID() string
+	// GetVersionID returns an ID for the installed version of the project
+	GetVersionID() string
+}
+
+// ChangelogGetter can optionally be implemented by Updaters that can retrieve the release notes of a project
+type ChangelogGetter interface {
+	// GetChangelogs returns the changelogs of the versions after the one installed in oldMod, up to and including the
+	// one installed in newMod (newest first)
+	GetChangelogs(oldMod *Mod, newMod *Mod) ([]Changelog, error)
+}
+
+// Changelog stores the release notes of a single version of a project
+type Changelog struct {
+	// Version is the human-readable name of the version
+	Version string
+	// Text is the release notes for the version, in Markdown or plain text
+	Text string
+}
+
+// DependencyChecker can optionally be implemented by Updaters that can retrieve the dependencies and compatibility
+// information of the installed version of each mod
+type DependencyChecker interface {
+	// GetDependencyInfo returns dependency information for each of the given mods
+	GetDependencyInfo([]*Mod, Pack) ([]DependencyInfo, error)
+}
+
+// DependencyInfo stores the dependencies and compatibility information of the installed version of a mod
+type DependencyInfo struct {
+	// Required stores the projects (IDs on this update system) that this mod requires
+	Required []Dependency
+	// Incompatible stores the projects (IDs on this update system) that this mod is incompatible with
+	Incompatible []Dependency
+	// Loaders stores the loaders supported by the installed version, or is empty if unknown
+	Loaders []string
+	// GameVersions stores the Minecraft versions supported by the installed version, or is empty if unknown
+	GameVersions []string
+	// Error stores an error for this specific mod
+	Error error
+}
+
+// Dependency is a project that another project depends on (or is incompatible with)
+type Dependency struct {
+	ProjectID string
+	Name      string
+}
+
+// FileMatcher can optionally be implemented by Updaters that can find files by their hashes, so that the update
+// metadata of mods added from another source can be stored alongside their existing metadata
+type FileMatcher interface {
+	// GetMatchHashFormats returns the hash formats that MatchFiles requires
+	GetMatchHashFormats() []string
+	// MatchFiles finds the file on this update system for each of the given mods, given the hashes of each mod's file
+	// (in the formats returned by GetMatchHashFormats); mods that couldn't be found are returned as nil
+	MatchFiles(mods []*Mod, hashes []map[string]string) ([]*FileMatch, error)
+}
+
+// FileMatch stores the file that was found for a mod by a FileMatcher
+type FileMatch struct {
+	// UpdateData is the update metadata to store for this update system
+	UpdateData map[string]interface{}
+	// DownloadURL is a URL the file can be downloaded from directly, or is empty if there isn't one
+	DownloadURL string
+}
+
+// SourceMigrator can optionally be implemented by Updaters that files from other sources can be migrated to
+type SourceMigrator interface {
+	// GetMigrateHashFormats returns the hash formats that FindMigrationFile requires
+	GetMigrateHashFormats() []string
+	// FindMigrationFile finds the file on this update system to replace the given mod's file with, given the hashes of
+	// the mod's file (in the formats returned by GetMigrateHashFormats). If the same file can't be found, the latest
+	// file of the project that is compatible with the pack is returned instead. The project (an ID, slug or repository,
+	// depending on the update system) can be given to use instead of finding the project from the mod.
+	FindMigrationFile(mod *Mod, project string, hashes map[string]string, pack Pack) (MigrationFile, error)
+}
+
+// MigrationFile stores the file that was found for a mod by a SourceMigrator
+type MigrationFile struct {
+	FileName   string
+	Download   ModDownload
+	UpdateData map[string]interface{}
+	// Exact is true if the file is the same as the mod's current file, and false if it is the nearest version found
+	Exact bool
+	// Version is the human-readable name of the version that was found
+	Version string
+}
+
 // MetaDownloaders stores all the metadata-based installers that packwiz can use. Add your own downloaders to this map, keyed by the source name.
 var MetaDownloaders = make(map[string]MetaDownloader)
 
@@ -48,6 +141,12 @@ type MetaDownloaderData interface {
 	DownloadFile() (io.ReadCloser, error)
 }
 
+// MetaDownloaderURLData can optionally be implemented by MetaDownloaderData for files that can be downloaded from a URL,
+// which is used instead of DownloadFile so that failed downloads can be retried and resumed
+type MetaDownloaderURLData interface {
+	GetDownloadURL() string
+}
+
 type ManualDownload struct {
 	Name     string
 	FileName string
@@ -0,0 +1,78 @@
+package core
+
+import (
+	"errors"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// DefaultLocalStoreFolder is the folder (relative to pack.toml) that local files are stored in, when local-store is not set
+const DefaultLocalStoreFolder = "local-store"
+
+// GetLocalStorePath returns the path of the folder that local files are stored in
+func GetLocalStorePath() string {
+	folder := viper.GetString("local-store")
+	if folder == "" {
+		folder = DefaultLocalStoreFolder
+	}
+	return filepath.Join(filepath.Dir(viper.GetString("pack-file")), filepath.FromSlash(folder))
+}
+
+// IsInLocalStore returns true if the given path is inside the folder that local files are stored in
+func IsInLocalStore(path string) bool {
+	absStore, err := filepath.Abs(GetLocalStorePath())
+	if err != nil {
+		return false
+	}
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return false
+	}
+	rel, err := filepath.Rel(absStore, absPath)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// IsLocalURL returns true if a download URL is relative (to the metadata file), so the file is stored with the pack
+func IsLocalURL(u string) bool {
+	parsed, err := url.Parse(u)
+	return err == nil && !parsed.IsAbs() && parsed.Host == "" && parsed.Path != ""
+}
+
+// GetLocalURL returns the relative download URL of a file stored with the pack, for the given metadata file
+func GetLocalURL(metaFile string, path string) (string, error) {
+	rel, err := filepath.Rel(filepath.Dir(metaFile), path)
+	if err != nil {
+		return "", err
+	}
+	segments := strings.Split(filepath.ToSlash(rel), "/")
+	for i, v := range segments {
+		segments[i] = url.PathEscape(v)
+	}
+	return strings.Join(segments, "/"), nil
+}
+
+// GetLocalFilePath returns the path of the file of a mod that is stored with the pack, resolving its download URL
+// relative to the metadata file
+func (m Mod) GetLocalFilePath() (string, error) {
+	parsed, err := url.Parse(m.Download.URL)
+	if err != nil {
+		return "", err
+	}
+	if parsed.IsAbs() || parsed.Host != "" {
+		return "", errors.New("download URL is not relative to the metadata file")
+	}
+	return filepath.Join(filepath.Dir(m.metaFile), filepath.FromSlash(parsed.Path)), nil
+}
+
+// OpenLocalFile opens the file of a mod that is stored with the pack
+func (m Mod) OpenLocalFile() (*os.File, error) {
+	path, err := m.GetLocalFilePath()
+	if err != nil {
+		return nil, err
+	}
+	return os.Open(path)
+}
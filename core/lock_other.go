@@ -0,0 +1,18 @@
+//go:build !windows
+
+package core
+
+import (
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+// lockFile acquires an exclusive advisory lock on a file, waiting until it is available
+func lockFile(f *os.File) error {
+	return unix.Flock(int(f.Fd()), unix.LOCK_EX)
+}
+
+func unlockFile(f *os.File) error {
+	return unix.Flock(int(f.Fd()), unix.LOCK_UN)
+}
@@ -0,0 +1,16 @@
+package core
+
+import (
+	"os"
+
+	"golang.org/x/sys/windows"
+)
+
+// lockFile acquires an exclusive advisory lock on a file, waiting until it is available
+func lockFile(f *os.File) error {
+	return windows.LockFileEx(windows.Handle(f.Fd()), windows.LOCKFILE_EXCLUSIVE_LOCK, 0, 1, 0, &windows.Overlapped{})
+}
+
+func unlockFile(f *os.File) error {
+	return windows.UnlockFileEx(windows.Handle(f.Fd()), 0, 1, 0, &windows.Overlapped{})
+}
@@ -13,12 +13,14 @@ import (
 
 // Mod stores metadata about a mod. This is written to a TOML file for each mod.
 type Mod struct {
-	metaFile string      // The file for the metadata file, used as an ID
-	Name     string      `toml:"name"`
-	FileName string      `toml:"filename"`
-	Side     string      `toml:"side,omitempty"`
-	Pin      bool        `toml:"pin,omitempty"`
-	Download ModDownload `toml:"download"`
+	metaFile string // The file for the metadata file, used as an ID
+	Name     string `toml:"name"`
+	FileName string `toml:"filename"`
+	Side     string `toml:"side,omitempty"`
+	Pin      bool   `toml:"pin,omitempty"`
+	// Dependency is true if this file was added automatically as a dependency of another file
+	Dependency bool        `toml:"dependency,omitempty"`
+	Download   ModDownload `toml:"download"`
 	// Update is a map of map of stuff, so you can store arbitrary values on string keys to define updating
 	Update     map[string]map[string]interface{} `toml:"update"`
 	updateData map[string]interface{}
@@ -26,6 +28,9 @@ type Mod struct {
 	Option *ModOption `toml:"option,omitempty"`
 }
 
+// staleUpdateKey is the key set in the update metadata of an update system when it has been marked as stale
+const staleUpdateKey = "stale"
+
 const (
 	ModeURL string = "url"
 	ModeCF  string = "metadata:curseforge"
@@ -38,6 +43,8 @@ type ModDownload struct {
 	Hash       string `toml:"hash"`
 	// Mode defaults to modeURL (i.e. use URL when omitted or empty)
 	Mode string `toml:"mode,omitempty"`
+	// Mirrors are other URLs the file can be downloaded from, which are tried in order if downloading from URL fails
+	Mirrors []string `toml:"mirrors,omitempty"`
 }
 
 // ModOption specifies optional metadata for this mod file
@@ -64,6 +71,10 @@ func LoadMod(modFile string) (Mod, error) {
 	mod.updateData = make(map[string]interface{})
 	// Horrible reflection library to convert map[string]interface to proper struct
 	for k, v := range mod.Update {
+		if mod.IsUpdateStale(k) {
+			// Stale metadata refers to an older version of the file, so it isn't used
+			continue
+		}
 		updater, ok := Updaters[k]
 		if ok {
 			updateData, err := updater.ParseUpdate(v)
@@ -124,6 +135,22 @@ func (m Mod) GetParsedUpdateData(updaterName string) (interface{}, bool) {
 	return upd, ok
 }
 
+// IsUpdateStale returns true if the metadata for an update system has been marked as stale, as it refers to a version
+// of the file that is no longer installed (e.g. after updating from another update system)
+func (m Mod) IsUpdateStale(updaterName string) bool {
+	stale, _ := m.Update[updaterName][staleUpdateKey].(bool)
+	return stale
+}
+
+// MarkUpdateStale marks the metadata for an update system as stale, so it is kept (to be linked again later) but not
+// used for updating or exporting
+func (m *Mod) MarkUpdateStale(updaterName string) {
+	if data, ok := m.Update[updaterName]; ok {
+		data[staleUpdateKey] = true
+	}
+	delete(m.updateData, updaterName)
+}
+
 // GetFilePath is a clumsy hack that I made because Mod already stores it's path anyway
 func (m Mod) GetFilePath() string {
 	return m.metaFile
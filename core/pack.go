@@ -32,10 +32,11 @@ type Pack struct {
 	Options  map[string]interface{}            `toml:"options"`
 }
 
-const CurrentPackFormat = "packwiz:1.1.0"
+// CurrentPackFormat is the pack format of new packs; 1.2.0 added download mirrors to metadata files
+const CurrentPackFormat = "packwiz:1.2.0"
 
 var PackFormatConstraintAccepted = mustParseConstraint("~1")
-var PackFormatConstraintSuggestUpgrade = mustParseConstraint("~1.1")
+var PackFormatConstraintSuggestUpgrade = mustParseConstraint("<1.3.0-0")
 
 func mustParseConstraint(s string) *semver.Constraints {
 	c, err := semver.NewConstraint(s)
@@ -45,10 +46,28 @@ func mustParseConstraint(s string) *semver.Constraints {
 	return c
 }
 
-// LoadPack loads the modpack metadata to a Pack struct
+// LoadPack loads the modpack metadata to a Pack struct, and reads its options into viper
 func LoadPack() (Pack, error) {
+	modpack, err := LoadPackFile(viper.GetString("pack-file"))
+	if err != nil {
+		return Pack{}, err
+	}
+
+	// Read options into viper
+	if modpack.Options != nil {
+		err = viper.MergeConfigMap(modpack.Options)
+		if err != nil {
+			return Pack{}, err
+		}
+	}
+	return modpack, nil
+}
+
+// LoadPackFile loads the modpack metadata from a file to a Pack struct, without reading its options into viper (e.g.
+// for packs other than the one being worked on)
+func LoadPackFile(path string) (Pack, error) {
 	var modpack Pack
-	if _, err := toml.DecodeFile(viper.GetString("pack-file"), &modpack); err != nil {
+	if _, err := toml.DecodeFile(path, &modpack); err != nil {
 		return Pack{}, err
 	}
 
@@ -77,14 +96,6 @@ func LoadPack() (Pack, error) {
 	}
 	// TODO: suggest migration if necessary (primarily for 2.0.0)
 
-	// Read options into viper
-	if modpack.Options != nil {
-		err := viper.MergeConfigMap(modpack.Options)
-		if err != nil {
-			return Pack{}, err
-		}
-	}
-
 	if len(modpack.Index.File) == 0 {
 		modpack.Index.File = "index.toml"
 	}
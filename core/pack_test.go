@@ -0,0 +1,74 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/spf13/viper"
+)
+
+func TestLoadPackFileOptions(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "pack.toml")
+	err := os.WriteFile(path, []byte(`name = "Test"
+pack-format = "packwiz:1.2.0"
+[index]
+file = "index.toml"
+hash-format = "sha256"
+[options]
+test-option = "pack"
+`), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	viper.Set("pack-file", path)
+	t.Cleanup(func() {
+		viper.Set("pack-file", nil)
+		viper.Set("test-option", nil)
+	})
+
+	// The options of packs loaded with LoadPackFile must not apply to other packs
+	pack, err := LoadPackFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pack.Options["test-option"] != "pack" {
+		t.Errorf("Expected the options of the pack to be read, got %v", pack.Options)
+	}
+	if viper.IsSet("test-option") {
+		t.Error("Expected LoadPackFile not to read options into viper")
+	}
+
+	_, err = LoadPack()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v := viper.GetString("test-option"); v != "pack" {
+		t.Errorf("Expected LoadPack to read options into viper, got %q", v)
+	}
+}
+
+func TestPackFormatConstraints(t *testing.T) {
+	tests := []struct {
+		version        string
+		accepted       bool
+		suggestUpgrade bool
+	}{
+		{"1.0.0", true, false},
+		{"1.1.0", true, false},
+		{"1.2.0", true, false},
+		{"1.2.5", true, false},
+		{"1.3.0", true, true},
+		{"2.0.0", false, true},
+	}
+	for _, tt := range tests {
+		ver := semver.MustParse(tt.version)
+		if accepted := PackFormatConstraintAccepted.Check(ver); accepted != tt.accepted {
+			t.Errorf("PackFormatConstraintAccepted.Check(%s) = %v, want %v", tt.version, accepted, tt.accepted)
+		}
+		if suggest := !PackFormatConstraintSuggestUpgrade.Check(ver); suggest != tt.suggestUpgrade {
+			t.Errorf("upgrade suggested for %s = %v, want %v", tt.version, suggest, tt.suggestUpgrade)
+		}
+	}
+}
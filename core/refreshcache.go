@@ -0,0 +1,119 @@
+package core
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const refreshCacheVersion = 1
+
+// refreshCache stores the hashes of files from the last refresh of an index, along with their size and modification time,
+// so files that haven't changed don't need to be hashed again. It is stored in the local cache folder, not in the pack.
+type refreshCache struct {
+	path    string
+	entries map[string]refreshCacheEntry
+	updated map[string]refreshCacheEntry
+}
+
+type refreshCacheEntry struct {
+	Size    int64  `json:"size"`
+	ModTime int64  `json:"mtime"`
+	Hash    string `json:"hash"`
+}
+
+type refreshCacheFile struct {
+	Version uint32                       `json:"version"`
+	Files   map[string]refreshCacheEntry `json:"files"`
+}
+
+// loadRefreshCache loads the refresh cache for the given (absolute) index file path; if it can't be loaded, an empty
+// cache is returned
+func loadRefreshCache(indexPath string) *refreshCache {
+	cache := &refreshCache{
+		entries: make(map[string]refreshCacheEntry),
+		updated: make(map[string]refreshCacheEntry),
+	}
+	localCache, err := GetPackwizLocalCache()
+	if err != nil {
+		return cache
+	}
+	h, err := GetHashImpl("sha256")
+	if err != nil {
+		return cache
+	}
+	h.Write([]byte(indexPath))
+	cache.path = filepath.Join(localCache, "refresh", hex.EncodeToString(h.Sum(nil))+".json")
+
+	data, err := os.ReadFile(cache.path)
+	if err != nil {
+		return cache
+	}
+	var file refreshCacheFile
+	if json.Unmarshal(data, &file) == nil && file.Version == refreshCacheVersion && file.Files != nil {
+		cache.entries = file.Files
+	}
+	return cache
+}
+
+// hashFile returns the hash of a file, using the cached hash if the file hasn't changed since it was cached
+// This is safe to call from multiple goroutines (the cache isn't modified)
+func (c *refreshCache) hashFile(path string) (string, refreshCacheEntry, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", refreshCacheEntry{}, err
+	}
+	entry := refreshCacheEntry{
+		Size:    info.Size(),
+		ModTime: info.ModTime().UnixNano(),
+	}
+	absPath, err := filepath.Abs(path)
+	if err == nil {
+		if cached, ok := c.entries[absPath]; ok && cached.Size == entry.Size && cached.ModTime == entry.ModTime {
+			return cached.Hash, cached, nil
+		}
+	}
+
+	hash, err := hashFile(path)
+	if err != nil {
+		return "", refreshCacheEntry{}, err
+	}
+	// Don't cache recently modified files, as they could be modified again without changing the modification time
+	if time.Since(info.ModTime()) > 2*time.Second {
+		entry.Hash = hash
+	}
+	return hash, entry, nil
+}
+
+// update stores the entry for a file, to be saved in the cache
+func (c *refreshCache) update(path string, entry refreshCacheEntry) {
+	if entry.Hash == "" {
+		return
+	}
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return
+	}
+	c.updated[absPath] = entry
+}
+
+// save writes the entries updated in this refresh to the cache, removing entries for files that no longer exist
+// Errors are ignored, as the cache is only used to speed up refreshing
+func (c *refreshCache) save() {
+	if c.path == "" {
+		return
+	}
+	data, err := json.Marshal(refreshCacheFile{
+		Version: refreshCacheVersion,
+		Files:   c.updated,
+	})
+	if err != nil {
+		return
+	}
+	if os.MkdirAll(filepath.Dir(c.path), 0755) != nil {
+		return
+	}
+	_ = os.WriteFile(c.path, data, 0644)
+}
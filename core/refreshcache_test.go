@@ -0,0 +1,65 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestRefreshCacheInvalidation(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	dir := t.TempDir()
+	indexPath := filepath.Join(dir, "index.toml")
+	path := filepath.Join(dir, "file.txt")
+	modTime := time.Now().Add(-time.Hour)
+	writeFile := func(contents string, modTime time.Time) {
+		t.Helper()
+		if err := os.WriteFile(path, []byte(contents), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(path, modTime, modTime); err != nil {
+			t.Fatal(err)
+		}
+	}
+	hashAndSave := func() string {
+		t.Helper()
+		cache := loadRefreshCache(indexPath)
+		hash, entry, err := cache.hashFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		cache.update(path, entry)
+		cache.save()
+		return hash
+	}
+
+	writeFile("first", modTime)
+	first := hashAndSave()
+
+	// Same size and modification time, so the cached hash is used
+	writeFile("fir5t", modTime)
+	if hash := hashAndSave(); hash != first {
+		t.Errorf("Expected the cached hash %s, got %s", first, hash)
+	}
+
+	// A different modification time invalidates the cached hash
+	writeFile("fir5t", modTime.Add(time.Minute))
+	second := hashAndSave()
+	if second == first {
+		t.Error("Expected the hash to change after the file was modified")
+	}
+
+	// A different size invalidates the cached hash
+	writeFile("longer", modTime.Add(time.Minute))
+	if hash := hashAndSave(); hash == second {
+		t.Error("Expected the hash to change after the file size changed")
+	}
+
+	// Recently modified files aren't cached
+	writeFile("recent", time.Now())
+	cache := loadRefreshCache(indexPath)
+	if _, entry, err := cache.hashFile(path); err != nil || entry.Hash != "" {
+		t.Errorf("Expected a recently modified file not to be cached, got %+v (%v)", entry, err)
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"net/url"
 	"strings"
+
+	"github.com/spf13/viper"
 )
 
 // ReencodeURL re-encodes URLs for RFC3986 compliance; as CurseForge URLs aren't properly encoded
@@ -18,3 +20,42 @@ func ReencodeURL(u string) (string, error) {
 	}
 	return parsed.String(), nil
 }
+
+// APIInstance is the API token of an instance of a self-hostable service (e.g. GitLab or Forgejo)
+type APIInstance struct {
+	URL   string `mapstructure:"url"`
+	Token string `mapstructure:"token"`
+}
+
+// GetAPIToken returns the token to send with requests to the given API URL, for the service configured under the given
+// key. Tokens are only sent to the host they were configured for: <key>.token is used for the default instance at
+// defaultURL, and <key>.instances lists the URLs and tokens of other instances. Metadata files can specify any API URL,
+// so no token is sent to hosts that aren't configured.
+func GetAPIToken(key string, defaultURL string, apiURL string) string {
+	if sameHost(apiURL, defaultURL) {
+		return viper.GetString(key + ".token")
+	}
+	var instances []APIInstance
+	if err := viper.UnmarshalKey(key+".instances", &instances); err != nil {
+		return ""
+	}
+	for _, v := range instances {
+		if v.URL != "" && sameHost(apiURL, v.URL) {
+			return v.Token
+		}
+	}
+	return ""
+}
+
+// sameHost returns true if both URLs have the same scheme and host (including the port)
+func sameHost(a string, b string) bool {
+	parsedA, err := url.Parse(a)
+	if err != nil {
+		return false
+	}
+	parsedB, err := url.Parse(b)
+	if err != nil {
+		return false
+	}
+	return parsedA.Host != "" && strings.EqualFold(parsedA.Scheme, parsedB.Scheme) && strings.EqualFold(parsedA.Host, parsedB.Host)
+}
@@ -3,6 +3,7 @@ package curseforge
 import (
 	"errors"
 	"fmt"
+	"html"
 	"io"
 	"path/filepath"
 	"regexp"
@@ -176,7 +177,7 @@ func getPathForFile(gameID uint32, classID uint32, categoryID uint32, slug strin
 	return filepath.Join(viper.GetString("meta-folder-base"), metaFolder, slug+core.MetaExtension)
 }
 
-func createModFile(modInfo modInfo, fileInfo modFileInfo, index *core.Index, optionalDisabled bool) error {
+func createModFile(modInfo modInfo, fileInfo modFileInfo, index *core.Index, optionalDisabled bool, isDependency bool) error {
 	updateMap := make(map[string]map[string]interface{})
 	var err error
 
@@ -207,8 +208,9 @@ func createModFile(modInfo modInfo, fileInfo modFileInfo, index *core.Index, opt
 			Hash:       hash,
 			Mode:       core.ModeCF,
 		},
-		Option: optional,
-		Update: updateMap,
+		Option:     optional,
+		Update:     updateMap,
+		Dependency: isDependency,
 	}
 	path := modMeta.SetMetaPath(getPathForFile(modInfo.GameID, modInfo.ClassID, modInfo.PrimaryCategoryID, modInfo.Slug))
 
@@ -362,6 +364,14 @@ type cfUpdateData struct {
 	FileID    uint32 `mapstructure:"file-id"`
 }
 
+func (u cfUpdateData) GetProjectID() string {
+	return strconv.FormatUint(uint64(u.ProjectID), 10)
+}
+
+func (u cfUpdateData) GetVersionID() string {
+	return strconv.FormatUint(uint64(u.FileID), 10)
+}
+
 func (u cfUpdateData) ToMap() (map[string]interface{}, error) {
 	newMap := make(map[string]interface{})
 	err := mapstructure.Decode(u, &newMap)
@@ -431,6 +441,7 @@ func (u cfUpdater) CheckUpdate(mods []*core.Mod, pack core.Pack) ([]core.UpdateC
 			results[i] = core.UpdateCheck{
 				UpdateAvailable: true,
 				UpdateString:    v.FileName + " -> " + fileName,
+				NewFileName:     fileName,
 				CachedState:     cachedStateStore{modInfos[i], fileID, fileInfoData},
 			}
 		} else {
@@ -474,6 +485,45 @@ func (u cfUpdater) DoUpdate(mods []*core.Mod, cachedState []interface{}) error {
 	return nil
 }
 
+// GetChangelogs returns the changelog of the new file; CurseForge doesn't provide a way to list the files in between
+// without retrieving every file of the project
+func (u cfUpdater) GetChangelogs(oldMod *core.Mod, newMod *core.Mod) ([]core.Changelog, error) {
+	newRaw, ok := newMod.GetParsedUpdateData("curseforge")
+	if !ok {
+		return nil, errors.New("failed to parse update metadata")
+	}
+	newData := newRaw.(cfUpdateData)
+
+	fileInfoData, err := cfDefaultClient.getFileInfo(newData.ProjectID, newData.FileID)
+	if err != nil {
+		return nil, err
+	}
+	changelog, err := cfDefaultClient.getFileChangelog(newData.ProjectID, newData.FileID)
+	if err != nil {
+		return nil, err
+	}
+	return []core.Changelog{{
+		Version: fileInfoData.FriendlyName,
+		Text:    htmlToText(changelog),
+	}}, nil
+}
+
+var htmlLineBreakRegex = regexp.MustCompile(`(?i)<br\s*/?>|</p>|</div>|</h[1-6]>`)
+var htmlListItemRegex = regexp.MustCompile(`(?i)<li[^>]*>`)
+var htmlTagRegex = regexp.MustCompile(`<[^>]*>`)
+var blankLinesRegex = regexp.MustCompile(`\n{3,}`)
+
+// htmlToText converts the HTML changelogs returned by CurseForge into plain text
+func htmlToText(s string) string {
+	s = htmlLineBreakRegex.ReplaceAllString(s, "\n")
+	s = htmlListItemRegex.ReplaceAllString(s, "\n- ")
+	s = htmlTagRegex.ReplaceAllString(s, "")
+	s = html.UnescapeString(s)
+	s = strings.ReplaceAll(s, "\r", "")
+	s = blankLinesRegex.ReplaceAllString(s, "\n\n")
+	return strings.TrimSpace(s)
+}
+
 type cfExportData struct {
 	ProjectID uint32 `mapstructure:"project-id"`
 }
@@ -586,6 +636,10 @@ func (m *cfDownloadMetadata) GetManualDownload() (bool, core.ManualDownload) {
 	}
 }
 
+func (m *cfDownloadMetadata) GetDownloadURL() string {
+	return m.url
+}
+
 func (m *cfDownloadMetadata) DownloadFile() (io.ReadCloser, error) {
 	resp, err := core.GetWithUA(m.url, "application/octet-stream")
 	if err != nil {
@@ -612,3 +666,209 @@ func mapDepOverride(depID uint32, isQuilt bool, mcVersion string) uint32 {
 	}
 	return depID
 }
+
+func (u cfUpdater) GetDependencyInfo(mods []*core.Mod, pack core.Pack) ([]core.DependencyInfo, error) {
+	results := make([]core.DependencyInfo, len(mods))
+	isQuilt := slices.Contains(pack.GetCompatibleLoaders(), "quilt")
+	mcVersion, err := pack.GetMCVersion()
+	if err != nil {
+		return nil, err
+	}
+
+	fileIDs := make([]uint32, 0, len(mods))
+	for _, mod := range mods {
+		rawData, ok := mod.GetParsedUpdateData("curseforge")
+		if !ok {
+			continue
+		}
+		fileIDs = append(fileIDs, rawData.(cfUpdateData).FileID)
+	}
+	fileInfos, err := cfDefaultClient.getFileInfoMultiple(fileIDs)
+	if err != nil {
+		return nil, err
+	}
+	filesByID := make(map[uint32]modFileInfo)
+	for _, v := range fileInfos {
+		filesByID[v.ID] = v
+	}
+
+	var depModIDs []uint32
+	for _, v := range fileInfos {
+		for _, dep := range v.Dependencies {
+			if dep.Type == dependencyTypeRequired || dep.Type == dependencyTypeIncompatible {
+				depModIDs = append(depModIDs, mapDepOverride(dep.ModID, isQuilt, mcVersion))
+			}
+		}
+	}
+	depNames := make(map[uint32]string)
+	if len(depModIDs) > 0 {
+		slices.Sort(depModIDs)
+		depModIDs = slices.Compact(depModIDs)
+		depInfos, err := cfDefaultClient.getModInfoMultiple(depModIDs)
+		if err != nil {
+			return nil, err
+		}
+		for _, v := range depInfos {
+			depNames[v.ID] = v.Name
+		}
+	}
+
+	for i, mod := range mods {
+		rawData, ok := mod.GetParsedUpdateData("curseforge")
+		if !ok {
+			results[i] = core.DependencyInfo{Error: errors.New("failed to parse update metadata")}
+			continue
+		}
+		fileInfoData, ok := filesByID[rawData.(cfUpdateData).FileID]
+		if !ok {
+			results[i] = core.DependencyInfo{Error: errors.New("failed to get file data")}
+			continue
+		}
+
+		// CurseForge stores loaders in the same list as game versions
+		for _, v := range fileInfoData.GameVersions {
+			if _, ok := core.ModLoaders[strings.ToLower(v)]; ok {
+				results[i].Loaders = append(results[i].Loaders, strings.ToLower(v))
+			} else {
+				results[i].GameVersions = append(results[i].GameVersions, v)
+			}
+		}
+		for _, dep := range fileInfoData.Dependencies {
+			depID := mapDepOverride(dep.ModID, isQuilt, mcVersion)
+			name, ok := depNames[depID]
+			if !ok {
+				name = strconv.FormatUint(uint64(depID), 10)
+			}
+			d := core.Dependency{ProjectID: strconv.FormatUint(uint64(depID), 10), Name: name}
+			switch dep.Type {
+			case dependencyTypeRequired:
+				results[i].Required = append(results[i].Required, d)
+			case dependencyTypeIncompatible:
+				results[i].Incompatible = append(results[i].Incompatible, d)
+			}
+		}
+	}
+
+	return results, nil
+}
+
+func (u cfUpdater) GetMatchHashFormats() []string {
+	return []string{"murmur2"}
+}
+
+func (u cfUpdater) MatchFiles(mods []*core.Mod, hashes []map[string]string) ([]*core.FileMatch, error) {
+	results := make([]*core.FileMatch, len(mods))
+	fingerprints := make([]uint32, len(mods))
+	fingerprintList := make([]uint32, 0, len(mods))
+	for i, v := range hashes {
+		fingerprint, err := strconv.ParseUint(v["murmur2"], 10, 32)
+		if err != nil {
+			continue
+		}
+		fingerprints[i] = uint32(fingerprint)
+		fingerprintList = append(fingerprintList, uint32(fingerprint))
+	}
+	if len(fingerprintList) == 0 {
+		return results, nil
+	}
+	res, err := cfDefaultClient.getFingerprintInfo(fingerprintList)
+	if err != nil {
+		return nil, err
+	}
+	matchesByFingerprint := make(map[uint32]cfUpdateData)
+	for _, v := range res.ExactMatches {
+		matchesByFingerprint[v.File.Fingerprint] = cfUpdateData{ProjectID: v.ID, FileID: v.File.ID}
+	}
+
+	for i := range mods {
+		data, ok := matchesByFingerprint[fingerprints[i]]
+		if !ok || fingerprints[i] == 0 {
+			continue
+		}
+		updateData, err := data.ToMap()
+		if err != nil {
+			return nil, err
+		}
+		// Files are always downloaded using the metadata:curseforge mode, so don't provide a direct URL
+		results[i] = &core.FileMatch{UpdateData: updateData}
+	}
+	return results, nil
+}
+
+func (u cfUpdater) GetMigrateHashFormats() []string {
+	return u.GetMatchHashFormats()
+}
+
+func (u cfUpdater) FindMigrationFile(mod *core.Mod, project string, hashes map[string]string, pack core.Pack) (core.MigrationFile, error) {
+	matches, err := u.MatchFiles([]*core.Mod{mod}, []map[string]string{hashes})
+	if err != nil {
+		return core.MigrationFile{}, err
+	}
+	if matches[0] != nil {
+		return core.MigrationFile{
+			FileName: mod.FileName,
+			Download: core.ModDownload{
+				HashFormat: mod.Download.HashFormat,
+				Hash:       mod.Download.Hash,
+				Mode:       core.ModeCF,
+			},
+			UpdateData: matches[0].UpdateData,
+			Exact:      true,
+		}, nil
+	}
+
+	var modInfoData modInfo
+	if modID, err := strconv.ParseUint(project, 10, 32); err == nil {
+		modInfoData, err = cfDefaultClient.getModInfo(uint32(modID))
+		if err != nil {
+			return core.MigrationFile{}, err
+		}
+	} else {
+		// Metadata files default to being named after the project slug
+		if project == "" {
+			project = strings.TrimSuffix(filepath.Base(mod.GetFilePath()), core.MetaExtension)
+		}
+		results, err := cfDefaultClient.getSearch("", project, 432, 0, 0, "", modloaderTypeAny)
+		if err != nil {
+			return core.MigrationFile{}, err
+		}
+		if len(results) == 0 {
+			return core.MigrationFile{}, fmt.Errorf("failed to find project %s", project)
+		}
+		modInfoData = results[0]
+	}
+
+	mcVersions, err := pack.GetSupportedMCVersions()
+	if err != nil {
+		return core.MigrationFile{}, err
+	}
+	fileID, fileInfoData, _ := findLatestFile(modInfoData, mcVersions, pack.GetCompatibleLoaders())
+	if fileID == 0 {
+		return core.MigrationFile{}, errors.New("no files compatible with the pack were found")
+	}
+	if fileInfoData == nil {
+		fileInfo, err := cfDefaultClient.getFileInfo(modInfoData.ID, fileID)
+		if err != nil {
+			return core.MigrationFile{}, err
+		}
+		fileInfoData = &fileInfo
+	}
+	hash, hashFormat := fileInfoData.getBestHash()
+	updateData, err := cfUpdateData{
+		ProjectID: modInfoData.ID,
+		FileID:    fileID,
+	}.ToMap()
+	if err != nil {
+		return core.MigrationFile{}, err
+	}
+	return core.MigrationFile{
+		FileName: fileInfoData.FileName,
+		Download: core.ModDownload{
+			HashFormat: hashFormat,
+			Hash:       hash,
+			Mode:       core.ModeCF,
+		},
+		UpdateData: updateData,
+		Version:    fileInfoData.FileName,
+	}, nil
+}
@@ -0,0 +1,22 @@
+package curseforge
+
+import "testing"
+
+func TestHtmlToText(t *testing.T) {
+	tests := []struct {
+		html string
+		want string
+	}{
+		{"<p>Fixed a crash</p>", "Fixed a crash"},
+		{"Line one<br>Line two<BR />Line three", "Line one\nLine two\nLine three"},
+		{"<ul><li>First</li><li class=\"x\">Second</li></ul>", "- First\n- Second"},
+		{"<h2>Changes</h2><p>Added &lt;items&gt; &amp; blocks</p>", "Changes\nAdded <items> & blocks"},
+		{"<p>One</p>\r\n\r\n\r\n<p>Two</p>", "One\n\nTwo"},
+		{"  <div></div>  ", ""},
+	}
+	for _, tt := range tests {
+		if got := htmlToText(tt.html); got != tt.want {
+			t.Errorf("htmlToText(%q) = %q, want %q", tt.html, got, tt.want)
+		}
+	}
+}
@@ -259,7 +259,7 @@ var importCmd = &cobra.Command{
 				continue
 			}
 
-			err = createModFile(modInfoValue, modFileInfoValue, &index, v.OptionalDisabled)
+			err = createModFile(modInfoValue, modFileInfoValue, &index, v.OptionalDisabled, false)
 			if err != nil {
 				fmt.Printf("Failed to save project \"%s\": %s\n", modInfoValue.Name, err)
 				os.Exit(1)
@@ -297,6 +297,27 @@ func (c *cfApiClient) getFileInfo(modID uint32, fileID uint32) (modFileInfo, err
 	return infoRes.Data, nil
 }
 
+func (c *cfApiClient) getFileChangelog(modID uint32, fileID uint32) (string, error) {
+	var changelogRes struct {
+		Data string `json:"data"`
+	}
+
+	modIDStr := strconv.FormatUint(uint64(modID), 10)
+	fileIDStr := strconv.FormatUint(uint64(fileID), 10)
+
+	resp, err := c.makeGet("/v1/mods/" + modIDStr + "/files/" + fileIDStr + "/changelog")
+	if err != nil {
+		return "", fmt.Errorf("failed to request changelog for project ID %d, file ID %d: %w", modID, fileID, err)
+	}
+
+	err = json.NewDecoder(resp.Body).Decode(&changelogRes)
+	if err != nil && err != io.EOF {
+		return "", fmt.Errorf("failed to request changelog for project ID %d, file ID %d: %w", modID, fileID, err)
+	}
+
+	return changelogRes.Data, nil
+}
+
 func (c *cfApiClient) getFileInfoMultiple(fileIDs []uint32) ([]modFileInfo, error) {
 	var infoRes struct {
 		Data []modFileInfo `json:"data"`
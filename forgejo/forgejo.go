@@ -0,0 +1,21 @@
+package forgejo
+
+import (
+	"github.com/packwiz/packwiz/cmd"
+	"github.com/packwiz/packwiz/core"
+	"github.com/packwiz/packwiz/releases"
+	"github.com/spf13/cobra"
+)
+
+var forgejoCmd = &cobra.Command{
+	Use:     "forgejo",
+	Aliases: []string{"gitea", "fj"},
+	Short:   "Manage projects released on Forgejo or Gitea",
+}
+
+var fjUpdater = releases.Updater{Name: "forgejo", Host: &fjDefaultClient, DefaultApiURL: defaultApiURL}
+
+func init() {
+	cmd.Add(forgejoCmd)
+	core.Updaters["forgejo"] = fjUpdater
+}
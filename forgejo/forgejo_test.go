@@ -0,0 +1,46 @@
+package forgejo
+
+import (
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/packwiz/packwiz/releases/releasetest"
+)
+
+const testApiURL = "https://forgejo.example.com/api/v1"
+
+// registerRepo registers the API of a repository named Mod at owner/mod, serving the given releases
+func registerRepo(r *releasetest.Releases, tokens *releasetest.Tokens) {
+	repo, _ := httpmock.NewJsonResponder(200, Repo{ID: 1, Name: "Mod", FullName: "owner/mod"})
+	httpmock.RegisterResponder("GET", testApiURL+"/repos/owner/mod", tokens.Record(repo))
+	httpmock.RegisterResponder("GET", testApiURL+"/repos/owner/mod/releases", tokens.Record(r.Responder("limit", func(tags []string) interface{} {
+		fjReleases := make([]Release, len(tags))
+		for i, tag := range tags {
+			fjReleases[i] = Release{
+				TagName:         tag,
+				TargetCommitish: "main",
+				Assets:          []Asset{{Name: r.AssetName(tag), BrowserDownloadURL: r.AssetURL(tag)}},
+			}
+		}
+		return fjReleases
+	})))
+}
+
+func TestInstallAndUpdate(t *testing.T) {
+	httpmock.Activate(t)
+	r := releasetest.NewReleases("https://files.example.com")
+	registerRepo(r, &releasetest.Tokens{})
+
+	releasetest.CheckInstallAndUpdate(t, fjUpdater, testApiURL, "owner/mod", r)
+}
+
+func TestTokenScoping(t *testing.T) {
+	httpmock.Activate(t)
+	tokens := &releasetest.Tokens{Header: "Authorization", Prefix: "token "}
+	registerRepo(releasetest.NewReleases("https://files.example.com"), tokens)
+
+	releasetest.CheckTokenScoping(t, "forgejo", defaultApiURL, testApiURL, tokens, func() error {
+		_, err := fjDefaultClient.GetProject(testApiURL, "owner/mod")
+		return err
+	})
+}
@@ -0,0 +1,85 @@
+package forgejo
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/packwiz/packwiz/core"
+	"github.com/packwiz/packwiz/releases"
+	"github.com/spf13/cobra"
+)
+
+// installCmd represents the install command
+var installCmd = &cobra.Command{
+	Use:     "add [URL|slug]",
+	Short:   "Add a project from a Forgejo or Gitea repository URL or slug",
+	Aliases: []string{"install", "get"},
+	Args:    cobra.ArbitraryArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		pack, err := core.LoadPack()
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+
+		if len(args) == 0 || len(args[0]) == 0 {
+			fmt.Println("You must specify a Forgejo repository URL.")
+			os.Exit(1)
+		}
+
+		apiURL, slug, err := parseRepoArg(args[0], apiURLFlag)
+		if err != nil {
+			fmt.Printf("Failed to add project: %s\n", err)
+			os.Exit(1)
+		}
+
+		repo, err := fjDefaultClient.GetProject(apiURL, slug)
+		if err != nil {
+			fmt.Printf("Failed to add project: %s\n", err)
+			os.Exit(1)
+		}
+
+		err = fjUpdater.Install(apiURL, repo, installOptions, pack)
+		if err != nil {
+			fmt.Printf("Failed to add project: %s\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+// parseRepoArg interprets the argument as a repository URL (using the API of the same server, unless one is given), or
+// as a slug on the given API (or codeberg.org by default)
+func parseRepoArg(arg string, apiURL string) (string, string, error) {
+	if strings.HasPrefix(arg, "https://") || strings.HasPrefix(arg, "http://") {
+		u, err := url.Parse(arg)
+		if err != nil {
+			return "", "", err
+		}
+		if apiURL == "" {
+			apiURL = u.Scheme + "://" + u.Host + "/api/v1"
+		}
+		// Remove subpages of the repository, e.g. /releases
+		parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+		if len(parts) < 2 || parts[0] == "" || parts[1] == "" {
+			return "", "", errors.New("URL doesn't contain a repository")
+		}
+		return strings.TrimSuffix(apiURL, "/"), parts[0] + "/" + strings.TrimSuffix(parts[1], ".git"), nil
+	}
+	if apiURL == "" {
+		apiURL = defaultApiURL
+	}
+	return strings.TrimSuffix(apiURL, "/"), arg, nil
+}
+
+var apiURLFlag string
+var installOptions releases.InstallOptions
+
+func init() {
+	forgejoCmd.AddCommand(installCmd)
+
+	installCmd.Flags().StringVar(&apiURLFlag, "api-url", "", "The Forgejo or Gitea API URL, for self-hosted instances (defaults to the API of the server in the repository URL, or "+defaultApiURL+")")
+	installOptions.AddFlags(installCmd, true)
+}
@@ -0,0 +1,130 @@
+package forgejo
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/packwiz/packwiz/core"
+	"github.com/packwiz/packwiz/releases"
+)
+
+const defaultApiURL = "https://codeberg.org/api/v1"
+
+// releasesPerPage is the number of releases requested in each page (the default maximum of Forgejo and Gitea)
+const releasesPerPage = 50
+
+type fjApiClient struct {
+	httpClient *http.Client
+}
+
+var fjDefaultClient = fjApiClient{&http.Client{}}
+
+func (c *fjApiClient) makeGet(url string) (*http.Response, error) {
+	fjApiToken := core.GetAPIToken("forgejo", defaultApiURL, url)
+
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("User-Agent", core.UserAgent)
+	req.Header.Set("Accept", "application/json")
+	if fjApiToken != "" {
+		req.Header.Set("Authorization", "token "+fjApiToken)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode == 429 {
+		_ = resp.Body.Close()
+		return nil, fmt.Errorf("Forgejo API ratelimit exceeded")
+	}
+	if resp.StatusCode != 200 {
+		_ = resp.Body.Close()
+		return nil, fmt.Errorf("invalid response status: %v", resp.Status)
+	}
+
+	return resp, nil
+}
+
+func (c *fjApiClient) getJson(url string, v interface{}) error {
+	resp, err := c.makeGet(url)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(body, v)
+}
+
+type Repo struct {
+	ID       int    `json:"id"`
+	Name     string `json:"name"`      // "hello_world"
+	FullName string `json:"full_name"` // "owner/hello_world"
+}
+
+type Release struct {
+	TagName         string  `json:"tag_name"`
+	TargetCommitish string  `json:"target_commitish"` // The branch of the release
+	Name            string  `json:"name"`
+	Body            string  `json:"body"`
+	Draft           bool    `json:"draft"`
+	Prerelease      bool    `json:"prerelease"`
+	Assets          []Asset `json:"assets"`
+}
+
+type Asset struct {
+	Name               string `json:"name"`
+	BrowserDownloadURL string `json:"browser_download_url"`
+}
+
+func (c *fjApiClient) GetProject(apiURL string, slug string) (releases.Project, error) {
+	var repo Repo
+	err := c.getJson(apiURL+"/repos/"+slug, &repo)
+	if err != nil {
+		return releases.Project{}, err
+	}
+	if repo.FullName == "" {
+		return releases.Project{}, errors.New("invalid json while fetching project: " + slug)
+	}
+	return releases.Project{Name: repo.Name, Slug: repo.FullName}, nil
+}
+
+func (c *fjApiClient) GetReleases(apiURL string, slug string, page int) ([]releases.Release, error) {
+	var fjReleases []Release
+	err := c.getJson(apiURL+"/repos/"+slug+"/releases?limit="+strconv.Itoa(releasesPerPage)+"&page="+strconv.Itoa(page), &fjReleases)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]releases.Release, len(fjReleases))
+	for i, r := range fjReleases {
+		result[i] = releases.Release{
+			TagName:    r.TagName,
+			Name:       r.Name,
+			Body:       r.Body,
+			Branch:     r.TargetCommitish,
+			Draft:      r.Draft,
+			Prerelease: r.Prerelease,
+		}
+		for _, a := range r.Assets {
+			result[i].Assets = append(result[i].Assets, releases.Asset{Name: a.Name, DownloadURL: a.BrowserDownloadURL})
+		}
+	}
+	return result, nil
+}
+
+func (c *fjApiClient) GetAsset(asset releases.Asset) (*http.Response, error) {
+	// Attachments can be external links to other servers, so the API token isn't sent
+	return core.GetWithUA(asset.DownloadURL, "application/octet-stream")
+}
@@ -0,0 +1,33 @@
+package github
+
+import (
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/packwiz/packwiz/releases/releasetest"
+)
+
+func TestInstallAndUpdate(t *testing.T) {
+	httpmock.ActivateNonDefault(ghDefaultClient.httpClient)
+	t.Cleanup(httpmock.DeactivateAndReset)
+
+	r := releasetest.NewReleases("https://github.com/owner/mod/releases/download")
+	repo, err := httpmock.NewJsonResponder(200, Repo{ID: 1, Name: "Mod", FullName: "owner/mod"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	httpmock.RegisterResponder("GET", "https://api.github.com/repos/owner/mod", repo)
+	httpmock.RegisterResponder("GET", "https://api.github.com/repos/owner/mod/releases", r.Responder("per_page", func(tags []string) interface{} {
+		ghReleases := make([]Release, len(tags))
+		for i, tag := range tags {
+			ghReleases[i] = Release{
+				TagName:         tag,
+				TargetCommitish: "main",
+				Assets:          []Asset{{Name: r.AssetName(tag), BrowserDownloadURL: r.AssetURL(tag)}},
+			}
+		}
+		return ghReleases
+	}))
+
+	releasetest.CheckInstallAndUpdate(t, ghUpdaterDefault.Updater, "", "owner/mod", r)
+}
@@ -1,16 +1,23 @@
 package github
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"strconv"
 
 	"github.com/packwiz/packwiz/core"
+	"github.com/packwiz/packwiz/releases"
 	"github.com/spf13/viper"
 )
 
 const ghApiServer = "api.github.com"
 
+// releasesPerPage is the number of releases requested in each page
+const releasesPerPage = 100
+
 type ghApiClient struct {
 	httpClient *http.Client
 }
@@ -43,14 +50,17 @@ func (c *ghApiClient) makeGet(url string) (*http.Response, error) {
 	if ratelimit_header != "" {
 		ratelimit, err = strconv.Atoi(ratelimit_header)
 		if err != nil {
+			_ = resp.Body.Close()
 			return nil, err
 		}
 	}
 
 	if resp.StatusCode == 403 && ratelimit == 0 {
+		_ = resp.Body.Close()
 		return nil, fmt.Errorf("GitHub API ratelimit exceeded; time of reset: %v", resp.Header.Get("x-ratelimit-reset"))
 	}
 	if resp.StatusCode != 200 {
+		_ = resp.Body.Close()
 		return nil, fmt.Errorf("invalid response status: %v", resp.Status)
 	}
 
@@ -62,20 +72,81 @@ func (c *ghApiClient) makeGet(url string) (*http.Response, error) {
 	return resp, nil
 }
 
-func (c *ghApiClient) getRepo(slug string) (*http.Response, error) {
-	resp, err := c.makeGet("https://" + ghApiServer + "/repos/" + slug)
+func (c *ghApiClient) getJson(url string, v interface{}) error {
+	resp, err := c.makeGet(url)
 	if err != nil {
-		return resp, err
+		return err
 	}
+	defer resp.Body.Close()
 
-	return resp, nil
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(body, v)
+}
+
+type Repo struct {
+	ID       int    `json:"id"`
+	Name     string `json:"name"`      // "hello_world"
+	FullName string `json:"full_name"` // "owner/hello_world"
 }
 
-func (c *ghApiClient) getReleases(slug string) (*http.Response, error) {
-	resp, err := c.getRepo(slug + "/releases")
+type Release struct {
+	URL             string  `json:"url"`
+	TagName         string  `json:"tag_name"`
+	TargetCommitish string  `json:"target_commitish"` // The branch of the release
+	Name            string  `json:"name"`
+	CreatedAt       string  `json:"created_at"`
+	Body            string  `json:"body"`
+	Draft           bool    `json:"draft"`
+	Prerelease      bool    `json:"prerelease"`
+	Assets          []Asset `json:"assets"`
+}
+
+type Asset struct {
+	URL                string `json:"url"`
+	BrowserDownloadURL string `json:"browser_download_url"`
+	Name               string `json:"name"`
+}
+
+// GetProject returns a repository; GitHub only has one instance, so the API URL isn't used
+func (c *ghApiClient) GetProject(_ string, slug string) (releases.Project, error) {
+	var repo Repo
+	err := c.getJson("https://"+ghApiServer+"/repos/"+slug, &repo)
 	if err != nil {
-		return resp, err
+		return releases.Project{}, err
+	}
+	if repo.FullName == "" {
+		return releases.Project{}, errors.New("invalid json while fetching project: " + slug)
 	}
+	return releases.Project{Name: repo.Name, Slug: repo.FullName}, nil
+}
 
-	return resp, nil
+func (c *ghApiClient) GetReleases(_ string, slug string, page int) ([]releases.Release, error) {
+	var ghReleases []Release
+	err := c.getJson("https://"+ghApiServer+"/repos/"+slug+"/releases?per_page="+strconv.Itoa(releasesPerPage)+"&page="+strconv.Itoa(page), &ghReleases)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]releases.Release, len(ghReleases))
+	for i, r := range ghReleases {
+		result[i] = releases.Release{
+			TagName:    r.TagName,
+			Name:       r.Name,
+			Body:       r.Body,
+			Branch:     r.TargetCommitish,
+			Draft:      r.Draft,
+			Prerelease: r.Prerelease,
+		}
+		for _, a := range r.Assets {
+			result[i].Assets = append(result[i].Assets, releases.Asset{Name: a.Name, DownloadURL: a.BrowserDownloadURL})
+		}
+	}
+	return result, nil
+}
+
+func (c *ghApiClient) GetAsset(asset releases.Asset) (*http.Response, error) {
+	// TODO potentionally cache downloads to speed things up and avoid getting ratelimited by github!
+	return c.makeGet(asset.DownloadURL)
 }
@@ -2,122 +2,93 @@ package github
 
 import (
 	"errors"
-	"fmt"
 	"strings"
 
-	"github.com/dlclark/regexp2"
-	"github.com/mitchellh/mapstructure"
 	"github.com/packwiz/packwiz/core"
+	"github.com/packwiz/packwiz/releases"
 )
 
-type ghUpdateData struct {
-	Slug   string `mapstructure:"slug"`
-	Tag    string `mapstructure:"tag"`
-	Branch string `mapstructure:"branch"`
-	Regex  string `mapstructure:"regex"`
+// ghUpdater is the update system for GitHub releases, which also supports migrating files to GitHub
+type ghUpdater struct {
+	releases.Updater
 }
 
-type ghUpdater struct{}
-
-func (u ghUpdater) ParseUpdate(updateUnparsed map[string]interface{}) (interface{}, error) {
-	var updateData ghUpdateData
-	err := mapstructure.Decode(updateUnparsed, &updateData)
-	return updateData, err
-}
-
-type cachedStateStore struct {
-	Slug    string
-	Release Release
+func (u ghUpdater) GetMigrateHashFormats() []string {
+	return []string{"sha256"}
 }
 
-func (u ghUpdater) CheckUpdate(mods []*core.Mod, pack core.Pack) ([]core.UpdateCheck, error) {
-	results := make([]core.UpdateCheck, len(mods))
-
-	for i, mod := range mods {
-		rawData, ok := mod.GetParsedUpdateData("github")
-		if !ok {
-			results[i] = core.UpdateCheck{Error: errors.New("failed to parse update metadata")}
-			continue
-		}
-
-		data := rawData.(ghUpdateData)
-
-		newRelease, err := getLatestRelease(data.Slug, data.Branch)
-		if err != nil {
-			results[i] = core.UpdateCheck{Error: fmt.Errorf("failed to get latest release: %v", err)}
-			continue
-		}
-
-		if newRelease.TagName == data.Tag { // The latest release is the same as the installed one
-			results[i] = core.UpdateCheck{UpdateAvailable: false}
-			continue
-		}
-
-		expr := regexp2.MustCompile(data.Regex, 0)
-
-		if len(newRelease.Assets) == 0 {
-			results[i] = core.UpdateCheck{Error: errors.New("new release doesn't have any assets")}
-			continue
+func (u ghUpdater) FindMigrationFile(mod *core.Mod, project string, hashes map[string]string, pack core.Pack) (core.MigrationFile, error) {
+	if project == "" {
+		matches := GithubRegex.FindStringSubmatch(mod.Download.URL)
+		if len(matches) != 2 {
+			return core.MigrationFile{}, errors.New("the repository couldn't be found from the download URL; it must be given as a slug or URL")
 		}
+		project = matches[1]
+	} else if matches := GithubRegex.FindStringSubmatch(project); len(matches) == 2 {
+		project = matches[1]
+	}
+	repo, err := u.Host.GetProject("", project)
+	if err != nil {
+		return core.MigrationFile{}, err
+	}
+	ghReleases, err := u.Host.GetReleases("", repo.Slug, 1)
+	if err != nil {
+		return core.MigrationFile{}, err
+	}
+	if len(ghReleases) == 0 {
+		return core.MigrationFile{}, errors.New("repository doesn't have any releases")
+	}
 
-		var newFiles []Asset
-
-		for _, v := range newRelease.Assets {
-			bl, _ := expr.MatchString(v.Name)
-			if bl {
-				newFiles = append(newFiles, v)
+	// GitHub doesn't provide hashes of assets, so only assets with the same name are downloaded to compare them
+	for _, release := range ghReleases {
+		for _, asset := range release.Assets {
+			if asset.Name != mod.FileName {
+				continue
+			}
+			hash, err := releases.HashAsset(u.Host, asset)
+			if err != nil {
+				return core.MigrationFile{}, err
+			}
+			if strings.EqualFold(hash, hashes["sha256"]) {
+				return newMigrationFile(repo, release, asset, hash, true)
 			}
-		}
-
-		if len(newFiles) == 0 {
-			results[i] = core.UpdateCheck{Error: errors.New("release doesn't have any assets matching regex")}
-			continue
-		}
-
-		if len(newFiles) > 1 {
-			// TODO: also print file names
-			results[i] = core.UpdateCheck{Error: errors.New("release has more than one asset matching regex")}
-			continue
-		}
-
-		newFile := newFiles[0]
-
-		results[i] = core.UpdateCheck{
-			UpdateAvailable: true,
-			UpdateString:    mod.FileName + " -> " + newFile.Name,
-			CachedState:     cachedStateStore{data.Slug, newRelease},
 		}
 	}
 
-	return results, nil
+	release, err := u.GetLatestRelease("", repo.Slug, "", "", "")
+	if err != nil {
+		return core.MigrationFile{}, err
+	}
+	file, err := releases.MatchAsset(release, releases.DefaultRegex)
+	if err != nil {
+		return core.MigrationFile{}, err
+	}
+	hash, err := releases.HashAsset(u.Host, file)
+	if err != nil {
+		return core.MigrationFile{}, err
+	}
+	return newMigrationFile(repo, release, file, hash, false)
 }
 
-func (u ghUpdater) DoUpdate(mods []*core.Mod, cachedState []interface{}) error {
-	for i, mod := range mods {
-		modState := cachedState[i].(cachedStateStore)
-		var release = modState.Release
-
-		// yes, this is duplicated - i guess we should just cache asset + tag instead of entire release...?
-		var file = release.Assets[0]
-		for _, v := range release.Assets {
-			if strings.HasSuffix(v.Name, ".jar") {
-				file = v
-			}
-		}
-
-		hash, err := file.getSha256()
-		if err != nil {
-			return err
-		}
-
-		mod.FileName = file.Name
-		mod.Download = core.ModDownload{
-			URL:        file.BrowserDownloadURL,
+func newMigrationFile(repo releases.Project, release releases.Release, asset releases.Asset, hash string, exact bool) (core.MigrationFile, error) {
+	updateData, err := releases.UpdateData{
+		Slug:   repo.Slug,
+		Tag:    release.TagName,
+		Branch: release.Branch,
+		Regex:  releases.DefaultRegex,
+	}.ToMap()
+	if err != nil {
+		return core.MigrationFile{}, err
+	}
+	return core.MigrationFile{
+		FileName: asset.Name,
+		Download: core.ModDownload{
+			URL:        asset.DownloadURL,
 			HashFormat: "sha256",
 			Hash:       hash,
-		}
-		mod.Update["github"]["tag"] = release.TagName
-	}
-
-	return nil
+		},
+		UpdateData: updateData,
+		Exact:      exact,
+		Version:    release.TagName,
+	}, nil
 }
@@ -0,0 +1,21 @@
+package gitlab
+
+import (
+	"github.com/packwiz/packwiz/cmd"
+	"github.com/packwiz/packwiz/core"
+	"github.com/packwiz/packwiz/releases"
+	"github.com/spf13/cobra"
+)
+
+var gitlabCmd = &cobra.Command{
+	Use:     "gitlab",
+	Aliases: []string{"gl"},
+	Short:   "Manage projects released on GitLab",
+}
+
+var glUpdater = releases.Updater{Name: "gitlab", Host: &glDefaultClient, DefaultApiURL: defaultApiURL}
+
+func init() {
+	cmd.Add(gitlabCmd)
+	core.Updaters["gitlab"] = glUpdater
+}
@@ -0,0 +1,67 @@
+package gitlab
+
+import (
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/packwiz/packwiz/releases/releasetest"
+)
+
+const testApiURL = "https://gitlab.example.com/api/v4"
+
+// registerProject registers the API of a project named Mod at group/mod, serving the given releases
+func registerProject(r *releasetest.Releases, tokens *releasetest.Tokens) {
+	project, _ := httpmock.NewJsonResponder(200, Project{ID: 1, Name: "Mod", PathWithNamespace: "group/mod"})
+	httpmock.RegisterResponder("GET", testApiURL+"/projects/group%2Fmod", tokens.Record(project))
+	httpmock.RegisterResponder("GET", testApiURL+"/projects/group%2Fmod/releases", tokens.Record(r.Responder("per_page", func(tags []string) interface{} {
+		glReleases := make([]Release, len(tags))
+		for i, tag := range tags {
+			glReleases[i].TagName = tag
+			glReleases[i].Assets.Links = []Asset{{Name: r.AssetName(tag), URL: r.AssetURL(tag)}}
+		}
+		return glReleases
+	})))
+}
+
+func TestInstallAndUpdate(t *testing.T) {
+	httpmock.Activate(t)
+	r := releasetest.NewReleases("https://files.example.com")
+	registerProject(r, &releasetest.Tokens{})
+
+	releasetest.CheckInstallAndUpdate(t, glUpdater, testApiURL, "group/mod", r)
+}
+
+func TestTokenScoping(t *testing.T) {
+	httpmock.Activate(t)
+	tokens := &releasetest.Tokens{Header: "PRIVATE-TOKEN"}
+	registerProject(releasetest.NewReleases("https://files.example.com"), tokens)
+
+	releasetest.CheckTokenScoping(t, "gitlab", defaultApiURL, testApiURL, tokens, func() error {
+		_, err := glDefaultClient.GetProject(testApiURL, "group/mod")
+		return err
+	})
+}
+
+func TestParseProjectArg(t *testing.T) {
+	tests := []struct {
+		arg        string
+		apiURL     string
+		wantApiURL string
+		wantSlug   string
+	}{
+		{"group/mod", "", defaultApiURL, "group/mod"},
+		{"group/mod", testApiURL + "/", testApiURL, "group/mod"},
+		{"https://gitlab.example.com/group/subgroup/mod/-/releases", "", testApiURL, "group/subgroup/mod"},
+		{"https://gitlab.example.com/group/mod.git", "", testApiURL, "group/mod"},
+	}
+	for _, tt := range tests {
+		apiURL, slug, err := parseProjectArg(tt.arg, tt.apiURL)
+		if err != nil {
+			t.Errorf("parseProjectArg(%q, %q) failed: %v", tt.arg, tt.apiURL, err)
+			continue
+		}
+		if apiURL != tt.wantApiURL || slug != tt.wantSlug {
+			t.Errorf("parseProjectArg(%q, %q) = %s, %s, want %s, %s", tt.arg, tt.apiURL, apiURL, slug, tt.wantApiURL, tt.wantSlug)
+		}
+	}
+}
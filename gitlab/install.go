@@ -0,0 +1,85 @@
+package gitlab
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/packwiz/packwiz/core"
+	"github.com/packwiz/packwiz/releases"
+	"github.com/spf13/cobra"
+)
+
+// installCmd represents the install command
+var installCmd = &cobra.Command{
+	Use:     "add [URL|slug]",
+	Short:   "Add a project from a GitLab project URL or slug",
+	Aliases: []string{"install", "get"},
+	Args:    cobra.ArbitraryArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		pack, err := core.LoadPack()
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+
+		if len(args) == 0 || len(args[0]) == 0 {
+			fmt.Println("You must specify a GitLab project URL.")
+			os.Exit(1)
+		}
+
+		apiURL, slug, err := parseProjectArg(args[0], apiURLFlag)
+		if err != nil {
+			fmt.Printf("Failed to add project: %s\n", err)
+			os.Exit(1)
+		}
+
+		project, err := glDefaultClient.GetProject(apiURL, slug)
+		if err != nil {
+			fmt.Printf("Failed to add project: %s\n", err)
+			os.Exit(1)
+		}
+
+		err = glUpdater.Install(apiURL, project, installOptions, pack)
+		if err != nil {
+			fmt.Printf("Failed to add project: %s\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+// parseProjectArg interprets the argument as a project URL (using the API of the same server, unless one is given), or
+// as a slug on the given API (or gitlab.com by default)
+func parseProjectArg(arg string, apiURL string) (string, string, error) {
+	if strings.HasPrefix(arg, "https://") || strings.HasPrefix(arg, "http://") {
+		u, err := url.Parse(arg)
+		if err != nil {
+			return "", "", err
+		}
+		if apiURL == "" {
+			apiURL = u.Scheme + "://" + u.Host + "/api/v4"
+		}
+		// Remove subpages of the project, e.g. /-/releases
+		slug, _, _ := strings.Cut(strings.Trim(u.Path, "/"), "/-/")
+		if slug == "" {
+			return "", "", errors.New("URL doesn't contain a project")
+		}
+		return strings.TrimSuffix(apiURL, "/"), strings.TrimSuffix(slug, ".git"), nil
+	}
+	if apiURL == "" {
+		apiURL = defaultApiURL
+	}
+	return strings.TrimSuffix(apiURL, "/"), arg, nil
+}
+
+var apiURLFlag string
+var installOptions releases.InstallOptions
+
+func init() {
+	gitlabCmd.AddCommand(installCmd)
+
+	installCmd.Flags().StringVar(&apiURLFlag, "api-url", "", "The GitLab API URL, for self-hosted instances (defaults to the API of the server in the project URL, or "+defaultApiURL+")")
+	installOptions.AddFlags(installCmd, false)
+}
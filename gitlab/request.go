@@ -0,0 +1,139 @@
+package gitlab
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/packwiz/packwiz/core"
+	"github.com/packwiz/packwiz/releases"
+)
+
+const defaultApiURL = "https://gitlab.com/api/v4"
+
+// releasesPerPage is the number of releases requested in each page
+const releasesPerPage = 100
+
+type glApiClient struct {
+	httpClient *http.Client
+}
+
+var glDefaultClient = glApiClient{&http.Client{}}
+
+func (c *glApiClient) makeGet(url string) (*http.Response, error) {
+	glApiToken := core.GetAPIToken("gitlab", defaultApiURL, url)
+
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("User-Agent", core.UserAgent)
+	req.Header.Set("Accept", "application/json")
+	if glApiToken != "" {
+		req.Header.Set("PRIVATE-TOKEN", glApiToken)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode == 429 {
+		_ = resp.Body.Close()
+		return nil, fmt.Errorf("GitLab API ratelimit exceeded; time of reset: %v", resp.Header.Get("RateLimit-ResetTime"))
+	}
+	if resp.StatusCode != 200 {
+		_ = resp.Body.Close()
+		return nil, fmt.Errorf("invalid response status: %v", resp.Status)
+	}
+
+	return resp, nil
+}
+
+func (c *glApiClient) getJson(url string, v interface{}) error {
+	resp, err := c.makeGet(url)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(body, v)
+}
+
+type Project struct {
+	ID                int    `json:"id"`
+	Name              string `json:"name"`                // "hello_world"
+	PathWithNamespace string `json:"path_with_namespace"` // "group/hello_world"
+}
+
+type Release struct {
+	TagName         string `json:"tag_name"`
+	Name            string `json:"name"`
+	Description     string `json:"description"`
+	ReleasedAt      string `json:"released_at"`
+	UpcomingRelease bool   `json:"upcoming_release"`
+	Assets          struct {
+		Links []Asset `json:"links"`
+	} `json:"assets"`
+}
+
+type Asset struct {
+	Name           string `json:"name"`
+	URL            string `json:"url"`
+	DirectAssetURL string `json:"direct_asset_url"`
+}
+
+func (u Asset) getDownloadURL() string {
+	if u.DirectAssetURL != "" {
+		return u.DirectAssetURL
+	}
+	return u.URL
+}
+
+func (c *glApiClient) GetProject(apiURL string, slug string) (releases.Project, error) {
+	var project Project
+	err := c.getJson(apiURL+"/projects/"+url.PathEscape(slug), &project)
+	if err != nil {
+		return releases.Project{}, err
+	}
+	if project.PathWithNamespace == "" {
+		return releases.Project{}, errors.New("invalid json while fetching project: " + slug)
+	}
+	return releases.Project{Name: project.Name, Slug: project.PathWithNamespace}, nil
+}
+
+func (c *glApiClient) GetReleases(apiURL string, slug string, page int) ([]releases.Release, error) {
+	var glReleases []Release
+	err := c.getJson(apiURL+"/projects/"+url.PathEscape(slug)+"/releases?per_page="+strconv.Itoa(releasesPerPage)+"&page="+strconv.Itoa(page), &glReleases)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]releases.Release, len(glReleases))
+	for i, r := range glReleases {
+		result[i] = releases.Release{
+			TagName: r.TagName,
+			Name:    r.Name,
+			Body:    r.Description,
+			// Upcoming releases haven't been released yet
+			Draft: r.UpcomingRelease,
+		}
+		for _, a := range r.Assets.Links {
+			result[i].Assets = append(result[i].Assets, releases.Asset{Name: a.Name, DownloadURL: a.getDownloadURL()})
+		}
+	}
+	return result, nil
+}
+
+func (c *glApiClient) GetAsset(asset releases.Asset) (*http.Response, error) {
+	// Asset links can point to other servers, so the API token isn't sent
+	return core.GetWithUA(asset.DownloadURL, "application/octet-stream")
+}
@@ -0,0 +1,186 @@
+package local
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/packwiz/packwiz/core"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var installCmd = &cobra.Command{
+	Use:   "add [path]",
+	Short: "Add a file from disk, storing it with the pack",
+	Long: `Add a file from disk, storing it with the pack.
+The file is copied into the local store folder (local-store in pack.toml, defaulting to local-store/), named by its
+hash, and the metadata file downloads it from there using a URL relative to the metadata file. Files in the local store
+aren't added to the index, but are served by packwiz serve.`,
+	Aliases: []string{"install", "get"},
+	Args:    cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		pack, err := core.LoadPack()
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+
+		store, err := cmd.Flags().GetString("store")
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		if store != "" {
+			if pack.Options == nil {
+				pack.Options = make(map[string]interface{})
+			}
+			pack.Options["local-store"] = filepath.ToSlash(store)
+			viper.Set("local-store", filepath.ToSlash(store))
+		}
+		storePath := core.GetLocalStorePath()
+		rel, err := filepath.Rel(filepath.Dir(viper.GetString("pack-file")), storePath)
+		if err != nil || rel == "." || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			fmt.Println("The local store must be a folder inside the pack folder")
+			os.Exit(1)
+		}
+
+		hash, err := getHash(args[0])
+		if err != nil {
+			fmt.Println("Failed to read file:", err)
+			os.Exit(1)
+		}
+		fileName := filepath.Base(args[0])
+		destFile := filepath.Join(storePath, hash, fileName)
+		if _, err := os.Stat(destFile); err == nil {
+			fmt.Println("File is already in the local store")
+		} else {
+			err = copyFile(args[0], destFile)
+			if err != nil {
+				fmt.Println("Failed to copy file to the local store:", err)
+				os.Exit(1)
+			}
+		}
+
+		index, err := pack.LoadIndex()
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+
+		name, err := cmd.Flags().GetString("name")
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		if name == "" {
+			name = strings.TrimSuffix(fileName, filepath.Ext(fileName))
+		}
+		modMeta := core.Mod{
+			Name:     name,
+			FileName: fileName,
+			Side:     core.UniversalSide,
+			Download: core.ModDownload{
+				HashFormat: "sha256",
+				Hash:       hash,
+			},
+		}
+
+		folder := viper.GetString("meta-folder")
+		if folder == "" {
+			folder = "mods"
+		}
+		destPathName, err := cmd.Flags().GetString("meta-name")
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		if destPathName == "" {
+			destPathName = core.SlugifyName(name)
+		}
+		destPath := modMeta.SetMetaPath(filepath.Join(viper.GetString("meta-folder-base"), folder,
+			destPathName+core.MetaExtension))
+		modMeta.Download.URL, err = core.GetLocalURL(destPath, destFile)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+
+		format, metaHash, err := modMeta.Write()
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		err = index.RefreshFileWithHash(destPath, format, metaHash, true)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		err = index.Write()
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		err = pack.UpdateIndexHash()
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		err = pack.Write()
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		fmt.Printf("Successfully added %s (%s) from: %s\n", name, destPath, args[0])
+	},
+}
+
+func getHash(path string) (string, error) {
+	mainHasher, err := core.GetHashImpl("sha256")
+	if err != nil {
+		return "", err
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	_, err = io.Copy(mainHasher, f)
+	if err != nil {
+		return "", err
+	}
+	return mainHasher.HashToString(mainHasher.Sum(nil)), nil
+}
+
+func copyFile(src string, dest string) error {
+	err := os.MkdirAll(filepath.Dir(dest), 0755)
+	if err != nil {
+		return err
+	}
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.Create(dest)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(out, in)
+	if err != nil {
+		_ = out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+func init() {
+	localCmd.AddCommand(installCmd)
+
+	installCmd.Flags().String("name", "", "The name of the file (defaults to the file name without its extension)")
+	installCmd.Flags().String("meta-name", "", "Filename to use for the created metadata file (defaults to a name generated from the name)")
+	installCmd.Flags().String("store", "", "The folder to store local files in, relative to pack.toml (saved as local-store in pack.toml)")
+}
@@ -0,0 +1,15 @@
+package local
+
+import (
+	"github.com/packwiz/packwiz/cmd"
+	"github.com/spf13/cobra"
+)
+
+var localCmd = &cobra.Command{
+	Use:   "local",
+	Short: "Add files that aren't available on any site, storing them with the pack",
+}
+
+func init() {
+	cmd.Add(localCmd)
+}
@@ -2,9 +2,14 @@ package main
 
 import (
 	// Modules of packwiz
+	_ "github.com/packwiz/packwiz/cache"
 	"github.com/packwiz/packwiz/cmd"
 	_ "github.com/packwiz/packwiz/curseforge"
+	_ "github.com/packwiz/packwiz/forgejo"
 	_ "github.com/packwiz/packwiz/github"
+	_ "github.com/packwiz/packwiz/gitlab"
+	_ "github.com/packwiz/packwiz/local"
+	_ "github.com/packwiz/packwiz/maven"
 	_ "github.com/packwiz/packwiz/migrate"
 	_ "github.com/packwiz/packwiz/modrinth"
 	_ "github.com/packwiz/packwiz/settings"
@@ -0,0 +1,130 @@
+package maven
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+
+	"github.com/packwiz/packwiz/core"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// installCmd represents the install command
+var installCmd = &cobra.Command{
+	Use:     "add [repository URL] [group:artifact[:version][:classifier]]",
+	Short:   "Add an artifact from a Maven repository",
+	Aliases: []string{"install", "get"},
+	Args:    cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		pack, err := core.LoadPack()
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+
+		repo, err := url.Parse(args[0])
+		if err != nil {
+			fmt.Println("Failed to parse repository URL:", err)
+			os.Exit(1)
+		}
+		if repo.Scheme != "https" && repo.Scheme != "http" {
+			fmt.Println("Unsupported URL scheme:", repo.Scheme)
+			os.Exit(1)
+		}
+
+		data, err := parseCoordinates(args[0], args[1])
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		if data.Version == "" {
+			fmt.Println("Retrieving latest version...")
+			data.Version, err = data.getLatestVersion()
+			if err != nil {
+				fmt.Printf("Failed to get latest version: %v\n", err)
+				os.Exit(1)
+			}
+		}
+
+		hashFormat, hash, err := data.getHash(data.Version)
+		if err != nil {
+			fmt.Printf("Failed to retrieve hash: %v\n", err)
+			os.Exit(1)
+		}
+
+		index, err := pack.LoadIndex()
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+
+		updateMap := make(map[string]map[string]interface{})
+		updateMap["maven"], err = data.ToMap()
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+
+		modMeta := core.Mod{
+			Name:     data.ArtifactID,
+			FileName: data.getFileName(data.Version),
+			Side:     core.UniversalSide,
+			Download: core.ModDownload{
+				URL:        data.getURL(data.Version),
+				HashFormat: hashFormat,
+				Hash:       hash,
+			},
+			Update: updateMap,
+		}
+
+		folder := viper.GetString("meta-folder")
+		if folder == "" {
+			folder = "mods"
+		}
+		destPathName, err := cmd.Flags().GetString("meta-name")
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		if destPathName == "" {
+			destPathName = core.SlugifyName(data.ArtifactID)
+		}
+		destPath := modMeta.SetMetaPath(filepath.Join(viper.GetString("meta-folder-base"), folder,
+			destPathName+core.MetaExtension))
+
+		format, hash, err := modMeta.Write()
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		err = index.RefreshFileWithHash(destPath, format, hash, true)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		err = index.Write()
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		err = pack.UpdateIndexHash()
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		err = pack.Write()
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		fmt.Printf("Artifact \"%s\" successfully added! (%s)\n", data.GetProjectID(), modMeta.FileName)
+	},
+}
+
+func init() {
+	mavenCmd.AddCommand(installCmd)
+
+	installCmd.Flags().String("meta-name", "", "Filename to use for the created metadata file (defaults to a name generated from the artifact ID)")
+}
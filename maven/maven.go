@@ -0,0 +1,157 @@
+package maven
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/mitchellh/mapstructure"
+	"github.com/packwiz/packwiz/cmd"
+	"github.com/packwiz/packwiz/core"
+	"github.com/spf13/cobra"
+	"github.com/unascribed/FlexVer/go/flexver"
+)
+
+var mavenCmd = &cobra.Command{
+	Use:     "maven",
+	Aliases: []string{"mvn"},
+	Short:   "Manage files published on Maven repositories",
+}
+
+func init() {
+	cmd.Add(mavenCmd)
+	core.Updaters["maven"] = mvnUpdater{}
+}
+
+type mvnUpdateData struct {
+	Repository string `mapstructure:"repository"`
+	GroupID    string `mapstructure:"group-id"`
+	ArtifactID string `mapstructure:"artifact-id"`
+	Classifier string `mapstructure:"classifier,omitempty"`
+	Version    string `mapstructure:"version"`
+}
+
+func (u mvnUpdateData) GetProjectID() string {
+	id := u.GroupID + ":" + u.ArtifactID
+	if u.Classifier != "" {
+		id += ":" + u.Classifier
+	}
+	return id
+}
+
+func (u mvnUpdateData) GetVersionID() string {
+	return u.Version
+}
+
+func (u mvnUpdateData) ToMap() (map[string]interface{}, error) {
+	newMap := make(map[string]interface{})
+	err := mapstructure.Decode(u, &newMap)
+	return newMap, err
+}
+
+// parseCoordinates parses Maven coordinates in the form group:artifact[:version][:classifier]
+func parseCoordinates(repository string, coords string) (mvnUpdateData, error) {
+	parts := strings.Split(coords, ":")
+	if len(parts) < 2 || len(parts) > 4 {
+		return mvnUpdateData{}, fmt.Errorf("invalid Maven coordinates %s; must be in the form group:artifact[:version][:classifier]", coords)
+	}
+	for _, v := range parts[:2] {
+		if v == "" {
+			return mvnUpdateData{}, fmt.Errorf("invalid Maven coordinates %s; group and artifact must not be empty", coords)
+		}
+	}
+	data := mvnUpdateData{
+		Repository: strings.TrimSuffix(repository, "/"),
+		GroupID:    parts[0],
+		ArtifactID: parts[1],
+	}
+	if len(parts) > 2 {
+		data.Version = parts[2]
+	}
+	if len(parts) > 3 {
+		data.Classifier = parts[3]
+	}
+	return data, nil
+}
+
+func (u mvnUpdateData) getArtifactDir() string {
+	return u.Repository + "/" + strings.ReplaceAll(u.GroupID, ".", "/") + "/" + u.ArtifactID
+}
+
+// getFileName returns the file name of the artifact for the given version
+func (u mvnUpdateData) getFileName(version string) string {
+	name := u.ArtifactID + "-" + version
+	if u.Classifier != "" {
+		name += "-" + u.Classifier
+	}
+	return name + ".jar"
+}
+
+// getURL returns the download URL of the artifact for the given version
+func (u mvnUpdateData) getURL(version string) string {
+	return u.getArtifactDir() + "/" + version + "/" + u.getFileName(version)
+}
+
+func (u mvnUpdateData) getMetadata() (core.MavenMetadata, error) {
+	var metadata core.MavenMetadata
+	resp, err := core.GetWithUA(u.getArtifactDir()+"/maven-metadata.xml", "application/xml")
+	if err != nil {
+		return metadata, fmt.Errorf("failed to retrieve Maven metadata: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != 200 {
+		return metadata, fmt.Errorf("failed to retrieve Maven metadata: unexpected response status: %v", resp.Status)
+	}
+	err = xml.NewDecoder(resp.Body).Decode(&metadata)
+	if err != nil {
+		return metadata, fmt.Errorf("failed to parse Maven metadata: %w", err)
+	}
+	return metadata, nil
+}
+
+// getLatestVersion returns the newest non-snapshot version of the artifact, ordered using FlexVer
+func (u mvnUpdateData) getLatestVersion() (string, error) {
+	metadata, err := u.getMetadata()
+	if err != nil {
+		return "", err
+	}
+	var versions []string
+	for _, v := range metadata.Versioning.Versions.Version {
+		if !strings.HasSuffix(v, "-SNAPSHOT") {
+			versions = append(versions, v)
+		}
+	}
+	if len(versions) == 0 {
+		return "", errors.New("no versions found in Maven metadata")
+	}
+	flexver.VersionSlice(versions).Sort()
+	return versions[len(versions)-1], nil
+}
+
+// getHash retrieves the hash of the artifact for the given version from the .sha512 or .sha1 file next to it
+func (u mvnUpdateData) getHash(version string) (string, string, error) {
+	for _, hashFormat := range []string{"sha512", "sha1"} {
+		resp, err := core.GetWithUA(u.getURL(version)+"."+hashFormat, "text/plain")
+		if err != nil {
+			return "", "", fmt.Errorf("failed to retrieve %s hash: %w", hashFormat, err)
+		}
+		if resp.StatusCode != 200 {
+			_ = resp.Body.Close()
+			continue
+		}
+		body, err := io.ReadAll(resp.Body)
+		_ = resp.Body.Close()
+		if err != nil {
+			return "", "", fmt.Errorf("failed to retrieve %s hash: %w", hashFormat, err)
+		}
+		// Some repositories include the file name after the hash
+		fields := strings.Fields(string(body))
+		if len(fields) == 0 {
+			continue
+		}
+		return hashFormat, strings.ToLower(fields[0]), nil
+	}
+	return "", "", fmt.Errorf("no .sha512 or .sha1 file found for %s", u.getFileName(version))
+}
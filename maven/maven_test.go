@@ -0,0 +1,132 @@
+package maven
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/packwiz/packwiz/core"
+)
+
+const testMetadata = `<?xml version="1.0" encoding="UTF-8"?>
+<metadata>
+  <groupId>com.example</groupId>
+  <artifactId>mod</artifactId>
+  <versioning>
+    <latest>2.0.0-SNAPSHOT</latest>
+    <release>1.10.0</release>
+    <versions>
+      <version>1.2.0</version>
+      <version>1.10.0</version>
+      <version>1.9.0</version>
+      <version>2.0.0-SNAPSHOT</version>
+    </versions>
+  </versioning>
+</metadata>`
+
+func newTestRepository(t *testing.T) *httptest.Server {
+	t.Helper()
+	files := map[string]string{
+		"/repo/com/example/mod/maven-metadata.xml": testMetadata,
+		// Only a .sha1 file, including the file name after the hash
+		"/repo/com/example/mod/1.10.0/mod-1.10.0.jar.sha1": "ABCDEF0123  mod-1.10.0.jar\n",
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		contents, ok := files[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte(contents))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestParseCoordinates(t *testing.T) {
+	tests := []struct {
+		coords  string
+		want    mvnUpdateData
+		wantErr bool
+	}{
+		{"com.example:mod", mvnUpdateData{Repository: "https://repo", GroupID: "com.example", ArtifactID: "mod"}, false},
+		{"com.example:mod:1.0", mvnUpdateData{Repository: "https://repo", GroupID: "com.example", ArtifactID: "mod", Version: "1.0"}, false},
+		{"com.example:mod:1.0:client", mvnUpdateData{Repository: "https://repo", GroupID: "com.example", ArtifactID: "mod", Version: "1.0", Classifier: "client"}, false},
+		{"com.example", mvnUpdateData{}, true},
+		{":mod:1.0", mvnUpdateData{}, true},
+		{"a:b:c:d:e", mvnUpdateData{}, true},
+	}
+	for _, tt := range tests {
+		got, err := parseCoordinates("https://repo/", tt.coords)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseCoordinates(%q) error = %v, wantErr %v", tt.coords, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("parseCoordinates(%q) = %+v, want %+v", tt.coords, got, tt.want)
+		}
+	}
+}
+
+func TestGetLatestVersion(t *testing.T) {
+	server := newTestRepository(t)
+	data := mvnUpdateData{Repository: server.URL + "/repo", GroupID: "com.example", ArtifactID: "mod"}
+
+	// Snapshots are ignored, and versions are compared with FlexVer rather than as strings
+	version, err := data.getLatestVersion()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if version != "1.10.0" {
+		t.Errorf("Expected latest version 1.10.0, got %s", version)
+	}
+
+	missing := mvnUpdateData{Repository: server.URL + "/repo", GroupID: "com.example", ArtifactID: "missing"}
+	if _, err := missing.getLatestVersion(); err == nil {
+		t.Error("Expected an error for an artifact without metadata")
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	server := newTestRepository(t)
+	metaFile := filepath.Join(t.TempDir(), "mod.pw.toml")
+	err := os.WriteFile(metaFile, []byte(`name = "Mod"
+filename = "mod-1.2.0.jar"
+[download]
+url = "`+server.URL+`/repo/com/example/mod/1.2.0/mod-1.2.0.jar"
+hash-format = "sha1"
+hash = "0000"
+[update.maven]
+repository = "`+server.URL+`/repo"
+group-id = "com.example"
+artifact-id = "mod"
+version = "1.2.0"
+`), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mod, err := core.LoadMod(metaFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	checks, err := mvnUpdater{}.CheckUpdate([]*core.Mod{&mod}, core.Pack{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if checks[0].Error != nil || !checks[0].UpdateAvailable {
+		t.Fatalf("Expected an update, got %+v", checks[0])
+	}
+	err = mvnUpdater{}.DoUpdate([]*core.Mod{&mod}, []interface{}{checks[0].CachedState})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mod.FileName != "mod-1.10.0.jar" || mod.Download.HashFormat != "sha1" || mod.Download.Hash != "abcdef0123" {
+		t.Errorf("Unexpected file after update: %s %+v", mod.FileName, mod.Download)
+	}
+	if mod.Update["maven"]["version"] != "1.10.0" {
+		t.Errorf("Expected the version to be updated, got %v", mod.Update["maven"]["version"])
+	}
+}
@@ -0,0 +1,77 @@
+package maven
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/mitchellh/mapstructure"
+	"github.com/packwiz/packwiz/core"
+	"github.com/unascribed/FlexVer/go/flexver"
+)
+
+type mvnUpdater struct{}
+
+func (u mvnUpdater) ParseUpdate(updateUnparsed map[string]interface{}) (interface{}, error) {
+	var updateData mvnUpdateData
+	err := mapstructure.Decode(updateUnparsed, &updateData)
+	return updateData, err
+}
+
+func (u mvnUpdater) CheckUpdate(mods []*core.Mod, pack core.Pack) ([]core.UpdateCheck, error) {
+	results := make([]core.UpdateCheck, len(mods))
+
+	for i, mod := range mods {
+		rawData, ok := mod.GetParsedUpdateData("maven")
+		if !ok {
+			results[i] = core.UpdateCheck{Error: errors.New("failed to parse update metadata")}
+			continue
+		}
+		data := rawData.(mvnUpdateData)
+
+		newVersion, err := data.getLatestVersion()
+		if err != nil {
+			results[i] = core.UpdateCheck{Error: fmt.Errorf("failed to get latest version: %v", err)}
+			continue
+		}
+		// Only update to newer versions, in case the installed version was chosen manually
+		if newVersion == data.Version || !flexver.Less(data.Version, newVersion) {
+			results[i] = core.UpdateCheck{UpdateAvailable: false}
+			continue
+		}
+
+		results[i] = core.UpdateCheck{
+			UpdateAvailable: true,
+			UpdateString:    data.Version + " -> " + newVersion,
+			NewFileName:     data.getFileName(newVersion),
+			CachedState:     newVersion,
+		}
+	}
+
+	return results, nil
+}
+
+func (u mvnUpdater) DoUpdate(mods []*core.Mod, cachedState []interface{}) error {
+	for i, mod := range mods {
+		rawData, ok := mod.GetParsedUpdateData("maven")
+		if !ok {
+			return errors.New("failed to parse update metadata")
+		}
+		data := rawData.(mvnUpdateData)
+		version := cachedState[i].(string)
+
+		hashFormat, hash, err := data.getHash(version)
+		if err != nil {
+			return err
+		}
+
+		mod.FileName = data.getFileName(version)
+		mod.Download = core.ModDownload{
+			URL:        data.getURL(version),
+			HashFormat: hashFormat,
+			Hash:       hash,
+		}
+		mod.Update["maven"]["version"] = version
+	}
+
+	return nil
+}
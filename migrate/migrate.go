@@ -7,8 +7,8 @@ import (
 
 // migrateCmd represents the base command when called without any subcommands
 var migrateCmd = &cobra.Command{
-	Use:   "migrate [minecraft|loader]",
-	Short: "Migrate your Minecraft and loader versions to newer versions.",
+	Use:   "migrate [minecraft|loader|source]",
+	Short: "Migrate your Minecraft and loader versions to newer versions, or files to different sources.",
 }
 
 func init() {
@@ -0,0 +1,168 @@
+package migrate
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/packwiz/packwiz/cmdshared"
+	"github.com/packwiz/packwiz/core"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var sourceCommand = &cobra.Command{
+	Use:   "source [name]",
+	Short: "Migrate a file to be downloaded and updated from a different source.",
+	Long: `Migrate a file to be downloaded and updated from a different source.
+The same file is found on the new source by its hash where possible; otherwise the latest version compatible with
+the pack is used instead, after asking for confirmation (which requires --project in non-interactive mode).`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		target := viper.GetString("migrate.source.to")
+		var supported []string
+		for k, v := range core.Updaters {
+			if _, ok := v.(core.SourceMigrator); ok {
+				supported = append(supported, k)
+			}
+		}
+		slices.Sort(supported)
+		if !slices.Contains(supported, target) {
+			fmt.Printf("Can't migrate to %q; must be one of %s\n", target, strings.Join(supported, ", "))
+			os.Exit(1)
+		}
+		migrator := core.Updaters[target].(core.SourceMigrator)
+
+		fmt.Println("Loading modpack...")
+		pack, err := core.LoadPack()
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		index, err := pack.LoadIndex()
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		modPath, ok := index.FindMod(args[0])
+		if !ok {
+			fmt.Println("Can't find this file; please ensure you have run packwiz refresh and use the name of the .pw.toml file (defaults to the project slug)")
+			os.Exit(1)
+		}
+		modData, err := core.LoadMod(modPath)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		if updaterName, ok := cmdshared.GetPrimaryUpdater(&modData); ok && updaterName == target {
+			fmt.Printf("%s is already updated from %s!\n", modData.Name, target)
+			os.Exit(1)
+		}
+
+		fmt.Printf("Retrieving %s...\n", modData.FileName)
+		session, err := core.CreateDownloadSession([]*core.Mod{&modData}, migrator.GetMigrateHashFormats())
+		if err != nil {
+			fmt.Printf("Error retrieving external files: %v\n", err)
+			os.Exit(1)
+		}
+		cmdshared.ListManualDownloads(session)
+		var hashes map[string]string
+		for dl := range session.StartDownloads() {
+			if dl.Error != nil {
+				fmt.Printf("Download of %s (%s) failed: %v\n", dl.Mod.Name, dl.Mod.FileName, dl.Error)
+				os.Exit(1)
+			}
+			for _, warning := range dl.Warnings {
+				fmt.Printf("Warning for %s (%s): %v\n", dl.Mod.Name, dl.Mod.FileName, warning)
+			}
+			_ = dl.File.Close()
+			hashes = dl.Hashes
+		}
+		err = session.SaveIndex()
+		if err != nil {
+			fmt.Printf("Error saving cache index: %v\n", err)
+			os.Exit(1)
+		}
+
+		fmt.Printf("Finding %s on %s...\n", modData.Name, target)
+		file, err := migrator.FindMigrationFile(&modData, viper.GetString("migrate.source.project"), hashes, pack)
+		if err != nil {
+			fmt.Printf("Failed to find %s on %s: %v\n", modData.Name, target, err)
+			os.Exit(1)
+		}
+
+		ok, err = confirmMigration(&modData, target, file)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		if !ok {
+			fmt.Println("Cancelled!")
+			return
+		}
+		applyMigration(&modData, target, file)
+
+		format, hash, err := modData.Write()
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		err = index.RefreshFileWithHash(modPath, format, hash, true)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		err = index.Write()
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		err = pack.UpdateIndexHash()
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		err = pack.Write()
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		fmt.Printf("%s migrated to %s successfully!\n", modData.Name, target)
+	},
+}
+
+// confirmMigration checks that the file found on the target source should be used, asking the user when it isn't the
+// same file. In non-interactive mode, a different file is only used when the project was given explicitly.
+func confirmMigration(modData *core.Mod, target string, file core.MigrationFile) (bool, error) {
+	if file.Exact {
+		fmt.Printf("Found the same file on %s\n", target)
+		return true, nil
+	}
+	fmt.Printf("Warning: %s could not be found on %s; the latest compatible version can be used instead (%s -> %s)\n",
+		modData.FileName, target, modData.FileName, file.FileName)
+	if file.Version != "" {
+		fmt.Printf("Warning: version %s may differ from the current version; check that it works with the pack!\n", file.Version)
+	}
+	if viper.GetBool("non-interactive") && viper.GetString("migrate.source.project") == "" {
+		return false, fmt.Errorf("the same file couldn't be found on %s; use --project to migrate to a different file in non-interactive mode", target)
+	}
+	return cmdshared.PromptYesNo("Do you want to use this version instead? [Y/n]: "), nil
+}
+
+// applyMigration replaces the download and update metadata of a file with the file found on the target source
+func applyMigration(modData *core.Mod, target string, file core.MigrationFile) {
+	modData.FileName = file.FileName
+	modData.Download = file.Download
+	// Metadata from other sources is removed, so the file is only updated from the new source
+	modData.Update = map[string]map[string]interface{}{target: file.UpdateData}
+}
+
+func init() {
+	migrateCmd.AddCommand(sourceCommand)
+
+	sourceCommand.Flags().String("to", "", "The source to migrate to (modrinth, curseforge or github)")
+	_ = viper.BindPFlag("migrate.source.to", sourceCommand.Flags().Lookup("to"))
+	sourceCommand.Flags().String("project", "", "The project ID, slug or repository to use if the same file can't be found (defaults to the name of the .pw.toml file, or the repository of the download URL for github)")
+	_ = viper.BindPFlag("migrate.source.project", sourceCommand.Flags().Lookup("project"))
+}
@@ -0,0 +1,76 @@
+package migrate
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/packwiz/packwiz/core"
+	"github.com/spf13/viper"
+)
+
+func TestConfirmMigration(t *testing.T) {
+	t.Cleanup(func() {
+		viper.Set("non-interactive", nil)
+		viper.Set("migrate.source.project", nil)
+	})
+	viper.Set("non-interactive", true)
+
+	modData := core.Mod{Name: "Example", FileName: "example-1.0.jar"}
+	tests := []struct {
+		name    string
+		exact   bool
+		project string
+		want    bool
+		wantErr bool
+	}{
+		{"exact match", true, "", true, false},
+		{"exact match with project", true, "example", true, false},
+		{"different file without project", false, "", false, true},
+		{"different file with project", false, "example", true, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			viper.Set("migrate.source.project", tt.project)
+			file := core.MigrationFile{FileName: "example-1.1.jar", Version: "1.1", Exact: tt.exact}
+			got, err := confirmMigration(&modData, "modrinth", file)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("confirmMigration() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("confirmMigration() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestApplyMigration(t *testing.T) {
+	modData := core.Mod{
+		Name:     "Example",
+		FileName: "example-1.0.jar",
+		Download: core.ModDownload{Mode: core.ModeCF, HashFormat: "sha1", Hash: "abc"},
+		Update: map[string]map[string]interface{}{
+			"curseforge": {"project-id": 1, "file-id": 2},
+			"github":     {"slug": "example/example"},
+		},
+	}
+	file := core.MigrationFile{
+		FileName: "example-1.1.jar",
+		Download: core.ModDownload{
+			URL:        "https://cdn.modrinth.com/data/P/versions/V/example-1.1.jar",
+			HashFormat: "sha512",
+			Hash:       "def",
+		},
+		UpdateData: map[string]interface{}{"mod-id": "P", "version": "V"},
+	}
+
+	applyMigration(&modData, "modrinth", file)
+	if modData.FileName != file.FileName {
+		t.Errorf("Expected file name %s, got %s", file.FileName, modData.FileName)
+	}
+	if !reflect.DeepEqual(modData.Download, file.Download) {
+		t.Errorf("Expected download %v, got %v", file.Download, modData.Download)
+	}
+	if len(modData.Update) != 1 || modData.Update["modrinth"]["version"] != "V" {
+		t.Errorf("Expected only the modrinth update metadata, got %v", modData.Update)
+	}
+}
@@ -145,13 +145,6 @@ var exportCmd = &cobra.Command{
 					serverEnv = envInstalled
 				}
 
-				// Modrinth URLs must be RFC3986
-				u, err := core.ReencodeURL(dl.Mod.Download.URL)
-				if err != nil {
-					fmt.Printf("Error re-encoding download URL: %s\n", err.Error())
-					u = dl.Mod.Download.URL
-				}
-
 				manifestFiles = append(manifestFiles, PackFile{
 					Path:   path,
 					Hashes: hashes,
@@ -159,7 +152,7 @@ var exportCmd = &cobra.Command{
 						Client string `json:"client"`
 						Server string `json:"server"`
 					}{Client: clientEnv, Server: serverEnv},
-					Downloads: []string{u},
+					Downloads: getDownloads(dl.Mod.Download, restrictDomains),
 					FileSize:  uint32(fileSize),
 				})
 
@@ -261,18 +254,42 @@ var whitelistedHosts = []string{
 
 func canBeIncludedDirectly(mod *core.Mod, restrictDomains bool) bool {
 	if mod.Download.Mode == core.ModeURL || mod.Download.Mode == "" {
-		if !restrictDomains {
-			return true
+		// Files stored with the pack can't be downloaded from the exported pack
+		if core.IsLocalURL(mod.Download.URL) {
+			return false
 		}
+		return isAllowedURL(mod.Download.URL, restrictDomains)
+	}
+	return false
+}
 
-		modUrl, err := url.Parse(mod.Download.URL)
-		if err == nil {
-			if slices.Contains(whitelistedHosts, modUrl.Host) {
-				return true
-			}
+// getDownloads returns the download URLs of a file in a Modrinth pack: its URL, followed by its mirrors on domains
+// allowed by Modrinth
+func getDownloads(download core.ModDownload, restrictDomains bool) []string {
+	var downloads []string
+	for i, v := range append([]string{download.URL}, download.Mirrors...) {
+		// Mirrors on domains not allowed by Modrinth are left out
+		if i > 0 && !isAllowedURL(v, restrictDomains) {
+			continue
 		}
+		// Modrinth URLs must be RFC3986
+		u, err := core.ReencodeURL(v)
+		if err != nil {
+			fmt.Printf("Error re-encoding download URL: %s\n", err.Error())
+			u = v
+		}
+		downloads = append(downloads, u)
 	}
-	return false
+	return downloads
+}
+
+// isAllowedURL returns true if the given URL can be used in a Modrinth pack
+func isAllowedURL(u string, restrictDomains bool) bool {
+	if !restrictDomains {
+		return true
+	}
+	modUrl, err := url.Parse(u)
+	return err == nil && slices.Contains(whitelistedHosts, modUrl.Host)
 }
 
 func init() {
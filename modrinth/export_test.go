@@ -0,0 +1,46 @@
+package modrinth
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/packwiz/packwiz/core"
+)
+
+func TestGetDownloads(t *testing.T) {
+	download := core.ModDownload{
+		URL: "https://cdn.modrinth.com/data/abc/versions/1.0/mod 1.0.jar",
+		Mirrors: []string{
+			"https://github.com/owner/mod/releases/download/1.0/mod.jar",
+			"https://example.com/mod.jar",
+			"https://gitlab.com/owner/mod/-/releases/1.0/downloads/mod.jar",
+		},
+	}
+	tests := []struct {
+		restrictDomains bool
+		want            []string
+	}{
+		// Mirrors on domains not allowed by Modrinth are left out
+		{true, []string{
+			"https://cdn.modrinth.com/data/abc/versions/1.0/mod%201.0.jar",
+			"https://github.com/owner/mod/releases/download/1.0/mod.jar",
+			"https://gitlab.com/owner/mod/-/releases/1.0/downloads/mod.jar",
+		}},
+		{false, []string{
+			"https://cdn.modrinth.com/data/abc/versions/1.0/mod%201.0.jar",
+			"https://github.com/owner/mod/releases/download/1.0/mod.jar",
+			"https://example.com/mod.jar",
+			"https://gitlab.com/owner/mod/-/releases/1.0/downloads/mod.jar",
+		}},
+	}
+	for _, tt := range tests {
+		if got := getDownloads(download, tt.restrictDomains); !slices.Equal(got, tt.want) {
+			t.Errorf("getDownloads(restrictDomains = %v) = %v, want %v", tt.restrictDomains, got, tt.want)
+		}
+	}
+
+	// The URL of a file is always included, as files on other domains are added to the overrides instead
+	if got := getDownloads(core.ModDownload{URL: "https://example.com/mod.jar"}, true); !slices.Equal(got, []string{"https://example.com/mod.jar"}) {
+		t.Errorf("getDownloads() = %v, want only the file URL", got)
+	}
+}
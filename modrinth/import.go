@@ -0,0 +1,539 @@
+package modrinth
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	modrinthApi "codeberg.org/jmansfield/go-modrinth/modrinth"
+	"github.com/packwiz/packwiz/core"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// importCmd represents the import command
+var importCmd = &cobra.Command{
+	Use:   "import [.mrpack file]",
+	Short: "Import a Modrinth modpack from a .mrpack file",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		zr, err := zip.OpenReader(args[0])
+		if err != nil {
+			fmt.Printf("Error opening file: %s\n", err)
+			os.Exit(1)
+		}
+		defer zr.Close()
+
+		var manifest Pack
+		var manifestFound bool
+		for _, v := range zr.File {
+			if v.Name == "modrinth.index.json" {
+				manifest, err = readManifest(v)
+				if err != nil {
+					fmt.Printf("Error reading modrinth.index.json: %s\n", err)
+					os.Exit(1)
+				}
+				manifestFound = true
+				break
+			}
+		}
+		if !manifestFound {
+			fmt.Println("Can't find modrinth.index.json, is this a valid Modrinth pack?")
+			os.Exit(1)
+		}
+		if manifest.Game != "minecraft" {
+			fmt.Printf("Unsupported game %s\n", manifest.Game)
+			os.Exit(1)
+		}
+
+		packVersions := make(map[string]string)
+		for dep, version := range manifest.Dependencies {
+			component, ok := manifestDependencyComponents[dep]
+			if !ok {
+				fmt.Printf("Ignoring unknown dependency %s (version %s)\n", dep, version)
+				continue
+			}
+			packVersions[component] = version
+		}
+
+		pack, err := core.LoadPack()
+		if err != nil {
+			fmt.Println("Failed to load existing pack, creating a new one...")
+
+			// Create a new modpack
+			indexFilePath := viper.GetString("init.index-file")
+			_, err = os.Stat(indexFilePath)
+			if os.IsNotExist(err) {
+				// Create file
+				err = os.WriteFile(indexFilePath, []byte{}, 0644)
+				if err != nil {
+					fmt.Printf("Error creating index file: %s\n", err)
+					os.Exit(1)
+				}
+				fmt.Println(indexFilePath + " created!")
+			} else if err != nil {
+				fmt.Printf("Error checking index file: %s\n", err)
+				os.Exit(1)
+			}
+
+			pack = core.Pack{
+				Name:        manifest.Name,
+				Version:     manifest.VersionID,
+				Description: manifest.Summary,
+				PackFormat:  core.CurrentPackFormat,
+				Index: struct {
+					File       string `toml:"file"`
+					HashFormat string `toml:"hash-format"`
+					Hash       string `toml:"hash,omitempty"`
+				}{
+					File: indexFilePath,
+				},
+				Versions: packVersions,
+			}
+		} else {
+			if pack.Versions == nil {
+				pack.Versions = make(map[string]string)
+			}
+			for component, version := range packVersions {
+				packVersion, ok := pack.Versions[component]
+				if !ok {
+					fmt.Println("Set " + core.ComponentToFriendlyName(component) + " version to " + version)
+				} else if packVersion != version {
+					fmt.Println("Set " + core.ComponentToFriendlyName(component) + " version to " + version + " (previously " + packVersion + ")")
+				}
+				pack.Versions[component] = version
+			}
+		}
+		index, err := pack.LoadIndex()
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+
+		fmt.Println("Querying Modrinth API for file info...")
+		matches, err := lookupFilesByHash(manifest.Files)
+		if err != nil {
+			fmt.Printf("Failed to obtain file information: %s\n", err)
+			os.Exit(1)
+		}
+
+		successes := 0
+		for i, v := range manifest.Files {
+			if !isSafeImportPath(v.Path) {
+				fmt.Printf("Ignored file \"%s\" (unsafe path)\n", v.Path)
+				continue
+			}
+			side, option := getImportSideAndOption(v)
+			if matches[i] != nil {
+				err = createImportedFileMeta(matches[i], v.Path, side, option, &index)
+			} else {
+				err = createImportedURLFileMeta(v, side, option, &index)
+			}
+			if err != nil {
+				fmt.Printf("Failed to save file \"%s\": %s\n", v.Path, err)
+				os.Exit(1)
+			}
+			successes++
+		}
+		fmt.Printf("Successfully imported %d/%d files (%d found on Modrinth)!\n", successes, len(manifest.Files), countMatches(matches))
+
+		fmt.Println("Reading override files...")
+		copied, err := importOverrides(zr, &index)
+		if err != nil {
+			fmt.Printf("Failed to read override files: %s\n", err)
+			os.Exit(1)
+		}
+		if copied > 0 {
+			err = index.Refresh()
+			if err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+		} else {
+			fmt.Println("No files copied!")
+		}
+
+		err = index.Write()
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		err = pack.UpdateIndexHash()
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		err = pack.Write()
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+	},
+}
+
+// manifestDependencyComponents maps Modrinth pack dependency names to packwiz version components
+var manifestDependencyComponents = map[string]string{
+	"minecraft":     "minecraft",
+	"fabric-loader": "fabric",
+	"quilt-loader":  "quilt",
+	"forge":         "forge",
+	"neoforge":      "neoforge",
+}
+
+// Folders in the pack zip containing files to be copied directly into the pack, and the side they apply to
+var overrideFolders = []struct {
+	prefix string
+	side   string
+}{
+	{"overrides/", core.UniversalSide},
+	{"client-overrides/", core.ClientSide},
+	{"server-overrides/", core.ServerSide},
+}
+
+func readManifest(f *zip.File) (Pack, error) {
+	var manifest Pack
+	r, err := f.Open()
+	if err != nil {
+		return manifest, err
+	}
+	defer r.Close()
+	err = json.NewDecoder(r).Decode(&manifest)
+	return manifest, err
+}
+
+// isSafeImportPath checks that a path from a pack file does not escape the pack folder
+func isSafeImportPath(p string) bool {
+	cleaned := path.Clean(p)
+	return cleaned != "." && !path.IsAbs(cleaned) && cleaned != ".." && !strings.HasPrefix(cleaned, "../") && !strings.Contains(p, "\\")
+}
+
+func getImportSideAndOption(file PackFile) (string, *core.ModOption) {
+	if file.Env == nil {
+		return core.UniversalSide, nil
+	}
+	side := core.UniversalSide
+	if file.Env.Client == "unsupported" {
+		side = core.ServerSide
+	} else if file.Env.Server == "unsupported" {
+		side = core.ClientSide
+	}
+	if file.Env.Client == "optional" || file.Env.Server == "optional" {
+		return side, &core.ModOption{
+			Optional: true,
+			Default:  false,
+		}
+	}
+	return side, nil
+}
+
+type importMatch struct {
+	project *modrinthApi.Project
+	version *modrinthApi.Version
+	file    *modrinthApi.File
+}
+
+func countMatches(matches []*importMatch) int {
+	n := 0
+	for _, v := range matches {
+		if v != nil {
+			n++
+		}
+	}
+	return n
+}
+
+// lookupFilesByHash finds the Modrinth project/version/file for each pack file, preferring SHA512 over SHA1 hashes;
+// files that cannot be found are left as nil
+func lookupFilesByHash(files []PackFile) ([]*importMatch, error) {
+	matches := make([]*importMatch, len(files))
+	hashesByFormat := make(map[string][]string)
+	for _, v := range files {
+		if hash, ok := v.Hashes["sha512"]; ok {
+			hashesByFormat["sha512"] = append(hashesByFormat["sha512"], hash)
+		} else if hash, ok := v.Hashes["sha1"]; ok {
+			hashesByFormat["sha1"] = append(hashesByFormat["sha1"], hash)
+		}
+	}
+
+	versionsByHash := make(map[string]*modrinthApi.Version)
+	for hashFormat, hashes := range hashesByFormat {
+		res, err := mrDefaultClient.VersionFiles.GetFromHashes(hashes, hashFormat)
+		if err != nil {
+			return nil, fmt.Errorf("failed to look up %s hashes: %w", hashFormat, err)
+		}
+		for hash, version := range res {
+			versionsByHash[hashFormat+":"+strings.ToLower(hash)] = version
+		}
+	}
+
+	var projectIDs []string
+	for _, v := range versionsByHash {
+		if v.ProjectID != nil {
+			projectIDs = append(projectIDs, *v.ProjectID)
+		}
+	}
+	projectsByID := make(map[string]*modrinthApi.Project)
+	if len(projectIDs) > 0 {
+		projects, err := mrDefaultClient.Projects.GetMultiple(projectIDs)
+		if err != nil {
+			return nil, fmt.Errorf("failed to retrieve project data: %w", err)
+		}
+		for _, v := range projects {
+			if v.ID != nil {
+				projectsByID[*v.ID] = v
+			}
+		}
+	}
+
+	for i, v := range files {
+		for _, hashFormat := range []string{"sha512", "sha1"} {
+			hash, ok := v.Hashes[hashFormat]
+			if !ok {
+				continue
+			}
+			version, ok := versionsByHash[hashFormat+":"+strings.ToLower(hash)]
+			if !ok || version.ProjectID == nil {
+				break
+			}
+			project, ok := projectsByID[*version.ProjectID]
+			if !ok {
+				break
+			}
+			for _, file := range version.Files {
+				if strings.EqualFold(file.Hashes[hashFormat], hash) {
+					matches[i] = &importMatch{project, version, file}
+					break
+				}
+			}
+			break
+		}
+	}
+	return matches, nil
+}
+
+func writeImportedMeta(modMeta core.Mod, metaPath string, index *core.Index) error {
+	modMeta.SetMetaPath(metaPath)
+
+	// If the file already exists, this will overwrite it!!!
+	// TODO: Should this be improved?
+	// Current strategy is to go ahead and do stuff without asking, with the assumption that you are using
+	// VCS anyway.
+
+	format, hash, err := modMeta.Write()
+	if err != nil {
+		return err
+	}
+	return index.RefreshFileWithHash(metaPath, format, hash, true)
+}
+
+// createImportedFileMeta creates a metadata file with Modrinth update metadata, in the folder the pack installs the file to
+func createImportedFileMeta(match *importMatch, filePath string, side string, option *core.ModOption, index *core.Index) error {
+	updateMap := make(map[string]map[string]interface{})
+
+	var err error
+	updateMap["modrinth"], err = mrUpdateData{
+		ProjectID:        *match.project.ID,
+		InstalledVersion: *match.version.ID,
+	}.ToMap()
+	if err != nil {
+		return err
+	}
+
+	algorithm, hash := getBestHash(match.file)
+	if algorithm == "" {
+		return errors.New("file doesn't have a hash")
+	}
+
+	modMeta := core.Mod{
+		Name:     *match.project.Title,
+		FileName: path.Base(filePath),
+		Side:     side,
+		Download: core.ModDownload{
+			URL:        *match.file.URL,
+			HashFormat: algorithm,
+			Hash:       hash,
+		},
+		Option: option,
+		Update: updateMap,
+	}
+
+	var slug string
+	if match.project.Slug != nil {
+		slug = *match.project.Slug
+	} else {
+		slug = core.SlugifyName(*match.project.Title)
+	}
+	metaPath := index.ResolveIndexPath(path.Join(path.Dir(filePath), slug+core.MetaExtension))
+	err = writeImportedMeta(modMeta, metaPath, index)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Imported \"%s\" successfully! (%s)\n", modMeta.Name, modMeta.FileName)
+	return nil
+}
+
+// createImportedURLFileMeta creates a metadata file using the download URL of a file that could not be found on Modrinth
+func createImportedURLFileMeta(file PackFile, side string, option *core.ModOption, index *core.Index) error {
+	if len(file.Downloads) == 0 {
+		return errors.New("file has no download URLs")
+	}
+	hashFormat := "sha512"
+	hash, ok := file.Hashes[hashFormat]
+	if !ok {
+		hashFormat = "sha1"
+		hash, ok = file.Hashes[hashFormat]
+		if !ok {
+			return errors.New("file doesn't have a hash")
+		}
+	}
+
+	fileName := path.Base(file.Path)
+	name := strings.TrimSuffix(fileName, path.Ext(fileName))
+	modMeta := core.Mod{
+		Name:     name,
+		FileName: fileName,
+		Side:     side,
+		Download: core.ModDownload{
+			URL:        file.Downloads[0],
+			HashFormat: hashFormat,
+			Hash:       strings.ToLower(hash),
+			Mirrors:    file.Downloads[1:],
+		},
+		Option: option,
+	}
+
+	metaPath := index.ResolveIndexPath(path.Join(path.Dir(file.Path), core.SlugifyName(name)+core.MetaExtension))
+	err := writeImportedMeta(modMeta, metaPath, index)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Imported \"%s\" as a URL file (not found on Modrinth)\n", fileName)
+	return nil
+}
+
+type sidedOverride struct {
+	zipFile *zip.File
+	path    string
+	side    string
+	hashes  map[string]string
+}
+
+// importOverrides copies all override files into the pack; files in side-specific override folders that can be
+// found on Modrinth are imported as metadata files (as sides can only be specified for metadata files)
+func importOverrides(zr *zip.ReadCloser, index *core.Index) (int, error) {
+	copied := 0
+	var sided []sidedOverride
+	for _, folder := range overrideFolders {
+		for _, f := range zr.File {
+			if !strings.HasPrefix(f.Name, folder.prefix) || f.FileInfo().IsDir() {
+				continue
+			}
+			relPath := strings.TrimPrefix(f.Name, folder.prefix)
+			if !isSafeImportPath(relPath) {
+				fmt.Printf("Ignored file \"%s\" (unsafe path)\n", f.Name)
+				continue
+			}
+			if folder.side == core.UniversalSide {
+				err := extractImportFile(f, index.ResolveIndexPath(relPath))
+				if err != nil {
+					return copied, err
+				}
+				copied++
+				continue
+			}
+			hashes, err := hashImportFile(f)
+			if err != nil {
+				return copied, err
+			}
+			sided = append(sided, sidedOverride{f, relPath, folder.side, hashes})
+		}
+	}
+
+	if len(sided) == 0 {
+		return copied, nil
+	}
+
+	files := make([]PackFile, len(sided))
+	for i, v := range sided {
+		files[i] = PackFile{Path: v.path, Hashes: v.hashes}
+	}
+	matches, err := lookupFilesByHash(files)
+	if err != nil {
+		return copied, err
+	}
+	for i, v := range sided {
+		if matches[i] != nil {
+			err = createImportedFileMeta(matches[i], v.path, v.side, nil, index)
+			if err != nil {
+				return copied, err
+			}
+			continue
+		}
+		fmt.Printf("Warning: \"%s\" is only installed on the %s side, but was not found on Modrinth; it will be installed on both sides\n", v.path, v.side)
+		err = extractImportFile(v.zipFile, index.ResolveIndexPath(v.path))
+		if err != nil {
+			return copied, err
+		}
+		copied++
+	}
+	return copied, nil
+}
+
+func hashImportFile(f *zip.File) (map[string]string, error) {
+	r, err := f.Open()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file \"%s\": %w", f.Name, err)
+	}
+	defer r.Close()
+
+	sha1Hasher, err := core.GetHashImpl("sha1")
+	if err != nil {
+		return nil, err
+	}
+	sha512Hasher, err := core.GetHashImpl("sha512")
+	if err != nil {
+		return nil, err
+	}
+	_, err = io.Copy(io.MultiWriter(sha1Hasher, sha512Hasher), r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file \"%s\": %w", f.Name, err)
+	}
+	return map[string]string{
+		"sha1":   sha1Hasher.HashToString(sha1Hasher.Sum(nil)),
+		"sha512": sha512Hasher.HashToString(sha512Hasher.Sum(nil)),
+	}, nil
+}
+
+func extractImportFile(f *zip.File, dest string) error {
+	src, err := f.Open()
+	if err != nil {
+		return fmt.Errorf("failed to read file \"%s\": %w", f.Name, err)
+	}
+	defer src.Close()
+
+	err = os.MkdirAll(filepath.Dir(dest), os.ModePerm)
+	if err != nil {
+		return fmt.Errorf("failed to create directories for \"%s\": %w", dest, err)
+	}
+	out, err := os.Create(dest)
+	if err != nil {
+		return fmt.Errorf("failed to write file \"%s\": %w", dest, err)
+	}
+	_, err = io.Copy(out, src)
+	if err != nil {
+		_ = out.Close()
+		return fmt.Errorf("failed to copy file \"%s\": %w", dest, err)
+	}
+	fmt.Printf("Copied file \"%s\" successfully!\n", dest)
+	return out.Close()
+}
+
+func init() {
+	modrinthCmd.AddCommand(importCmd)
+}
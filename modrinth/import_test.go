@@ -0,0 +1,214 @@
+package modrinth
+
+import (
+	"archive/zip"
+	"crypto/sha512"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/packwiz/packwiz/core"
+)
+
+func newTestIndex(t *testing.T) core.Index {
+	t.Helper()
+	indexFile := filepath.Join(t.TempDir(), "index.toml")
+	if err := os.WriteFile(indexFile, []byte(`hash-format = "sha256"`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	index, err := core.LoadIndex(indexFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return index
+}
+
+func parsePackFile(t *testing.T, data string) PackFile {
+	t.Helper()
+	var file PackFile
+	if err := json.Unmarshal([]byte(data), &file); err != nil {
+		t.Fatal(err)
+	}
+	return file
+}
+
+func TestGetImportSideAndOption(t *testing.T) {
+	tests := []struct {
+		file     string
+		side     string
+		optional bool
+	}{
+		{`{}`, core.UniversalSide, false},
+		{`{"env": {"client": "required", "server": "required"}}`, core.UniversalSide, false},
+		{`{"env": {"client": "required", "server": "unsupported"}}`, core.ClientSide, false},
+		{`{"env": {"client": "unsupported", "server": "required"}}`, core.ServerSide, false},
+		{`{"env": {"client": "optional", "server": "unsupported"}}`, core.ClientSide, true},
+		{`{"env": {"client": "required", "server": "optional"}}`, core.UniversalSide, true},
+	}
+	for _, tt := range tests {
+		side, option := getImportSideAndOption(parsePackFile(t, tt.file))
+		if side != tt.side {
+			t.Errorf("getImportSideAndOption(%s) side = %q, want %q", tt.file, side, tt.side)
+		}
+		if (option != nil) != tt.optional {
+			t.Errorf("getImportSideAndOption(%s) option = %+v, want optional %v", tt.file, option, tt.optional)
+		} else if option != nil && option.Default {
+			t.Errorf("getImportSideAndOption(%s) expected optional files to be disabled by default", tt.file)
+		}
+	}
+}
+
+func TestIsSafeImportPath(t *testing.T) {
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{"mods/mod.jar", true},
+		{"config/../options.txt", true},
+		{"", false},
+		{".", false},
+		{"..", false},
+		{"../mod.jar", false},
+		{"mods/../../mod.jar", false},
+		{"/etc/passwd", false},
+		{"mods\\mod.jar", false},
+	}
+	for _, tt := range tests {
+		if got := isSafeImportPath(tt.path); got != tt.want {
+			t.Errorf("isSafeImportPath(%q) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestCreateImportedURLFileMeta(t *testing.T) {
+	tests := []struct {
+		file       string
+		metaFile   string
+		url        string
+		mirrors    []string
+		hashFormat string
+		hash       string
+		wantErr    bool
+	}{
+		{`{"path": "mods/Some Mod.jar", "hashes": {"sha1": "AB", "sha512": "CD"}, "downloads": ["https://example.com/a.jar"]}`,
+			"mods/some-mod.pw.toml", "https://example.com/a.jar", nil, "sha512", "cd", false},
+		{`{"path": "resourcepacks/pack.zip", "hashes": {"sha1": "AB"}, "downloads": ["https://example.com/a.zip", "https://mirror.example.com/a.zip"]}`,
+			"resourcepacks/pack.pw.toml", "https://example.com/a.zip", []string{"https://mirror.example.com/a.zip"}, "sha1", "ab", false},
+		{`{"path": "mods/nohash.jar", "hashes": {}, "downloads": ["https://example.com/a.jar"]}`, "", "", nil, "", "", true},
+		{`{"path": "mods/nodownload.jar", "hashes": {"sha1": "AB"}, "downloads": []}`, "", "", nil, "", "", true},
+	}
+	for _, tt := range tests {
+		index := newTestIndex(t)
+		err := createImportedURLFileMeta(parsePackFile(t, tt.file), core.ClientSide, nil, &index)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("createImportedURLFileMeta(%s) error = %v, wantErr %v", tt.file, err, tt.wantErr)
+			continue
+		}
+		if tt.wantErr {
+			continue
+		}
+		mod, err := core.LoadMod(index.ResolveIndexPath(tt.metaFile))
+		if err != nil {
+			t.Errorf("createImportedURLFileMeta(%s) didn't write %s: %v", tt.file, tt.metaFile, err)
+			continue
+		}
+		if mod.Side != core.ClientSide || mod.Download.URL != tt.url || !slices.Equal(mod.Download.Mirrors, tt.mirrors) ||
+			mod.Download.HashFormat != tt.hashFormat || mod.Download.Hash != tt.hash {
+			t.Errorf("createImportedURLFileMeta(%s) wrote %+v", tt.file, mod)
+		}
+		if _, ok := index.Files[tt.metaFile]; !ok {
+			t.Errorf("createImportedURLFileMeta(%s) didn't add %s to the index", tt.file, tt.metaFile)
+		}
+	}
+}
+
+func TestImportOverrides(t *testing.T) {
+	httpmock.Activate(t)
+
+	files := map[string]string{
+		"overrides/config/mod.toml":             "config",
+		"overrides/../escape.txt":               "escape",
+		"client-overrides/mods/client-mod.jar":  "client mod",
+		"server-overrides/mods/server-only.jar": "server only",
+	}
+	clientHash := sha512.Sum512([]byte(files["client-overrides/mods/client-mod.jar"]))
+	clientHashStr := hex.EncodeToString(clientHash[:])
+
+	archivePath := filepath.Join(t.TempDir(), "pack.mrpack")
+	f, err := os.Create(archivePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	zw := zip.NewWriter(f)
+	for name, contents := range files {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.Write([]byte(contents)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	_ = f.Close()
+
+	// Only the client mod can be found on Modrinth
+	versions, err := httpmock.NewJsonResponder(200, map[string]interface{}{
+		clientHashStr: map[string]interface{}{
+			"id":         "version",
+			"project_id": "project",
+			"files": []map[string]interface{}{{
+				"url":    "https://cdn.modrinth.com/client-mod.jar",
+				"hashes": map[string]string{"sha512": clientHashStr},
+			}},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	httpmock.RegisterResponder("POST", "https://api.modrinth.com/v2/version_files", versions)
+	projects, err := httpmock.NewJsonResponder(200, []map[string]interface{}{{"id": "project", "slug": "client-mod", "title": "Client Mod"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	httpmock.RegisterResponder("GET", "https://api.modrinth.com/v2/projects", projects)
+
+	zr, err := zip.OpenReader(archivePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer zr.Close()
+	index := newTestIndex(t)
+	copied, err := importOverrides(zr, &index)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if copied != 2 {
+		t.Errorf("Expected 2 files to be copied, got %d", copied)
+	}
+
+	for _, v := range []string{"config/mod.toml", "mods/server-only.jar"} {
+		if _, err := os.Stat(index.ResolveIndexPath(v)); err != nil {
+			t.Errorf("Expected %s to be copied: %v", v, err)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(filepath.Dir(index.ResolveIndexPath("")), "escape.txt")); !os.IsNotExist(err) {
+		t.Error("Expected a file with an unsafe path not to be copied")
+	}
+	if _, err := os.Stat(index.ResolveIndexPath("mods/client-mod.jar")); !os.IsNotExist(err) {
+		t.Error("Expected the client mod found on Modrinth not to be copied")
+	}
+	mod, err := core.LoadMod(index.ResolveIndexPath("mods/client-mod.pw.toml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mod.Side != core.ClientSide || mod.FileName != "client-mod.jar" || mod.Download.URL != "https://cdn.modrinth.com/client-mod.jar" {
+		t.Errorf("Unexpected metadata for the client mod: %+v", mod)
+	}
+}
@@ -234,12 +234,16 @@ func installVersion(project *modrinthApi.Project, version *modrinthApi.Version,
 			return err
 		}
 
+		err = checkIncompatibleDependencies(version, installedProjects)
+		if err != nil {
+			return err
+		}
+
 		var depMetadata []depMetadataStore
 		var depProjectIDPendingQueue []string
 		var depVersionIDPendingQueue []string
 
 		for _, dep := range version.Dependencies {
-			// TODO: recommend optional dependencies?
 			if dep.DependencyType != nil && *dep.DependencyType == "required" {
 				if dep.VersionID != nil {
 					depVersionIDPendingQueue = append(depVersionIDPendingQueue, *dep.VersionID)
@@ -312,7 +316,6 @@ func installVersion(project *modrinthApi.Project, version *modrinthApi.Version,
 					}
 
 					for _, dep := range version.Dependencies {
-						// TODO: recommend optional dependencies?
 						if dep.DependencyType != nil && *dep.DependencyType == "required" {
 							if dep.ProjectID != nil {
 								depProjectIDPendingQueue = append(depProjectIDPendingQueue, mapDepOverride(*dep.ProjectID, isQuilt, mcVersion))
@@ -351,18 +354,35 @@ func installVersion(project *modrinthApi.Project, version *modrinthApi.Version,
 				}
 
 				if cmdshared.PromptYesNo("Would you like to add them? [Y/n]: ") {
+					err = addDependencies(depMetadata, pack, index, true)
+					if err != nil {
+						return err
+					}
 					for _, v := range depMetadata {
-						err := createFileMeta(v.projectInfo, v.versionInfo, v.fileInfo, pack, index)
-						if err != nil {
-							return err
-						}
-						fmt.Printf("Dependency \"%s\" successfully added! (%s)\n", *v.projectInfo.Title, *v.fileInfo.Filename)
+						installedProjects = append(installedProjects, *v.projectInfo.ID)
 					}
 				}
 			} else {
 				fmt.Println("All dependencies are already added!")
 			}
 		}
+
+		// Optional dependencies are chosen separately from required dependencies, so choosing them doesn't need another
+		// prompt and isn't affected by the answer above
+		optionalDeps, err := selectOptionalDependencies(version, installedProjects, isQuilt, mcVersion)
+		if err != nil {
+			return err
+		}
+		optionalMetadata, err := getLatestDependencyMetadata(optionalDeps, pack)
+		if err != nil {
+			return err
+		}
+		// Optional dependencies were chosen by the user, so they aren't marked as dependencies (which would allow them to
+		// be removed automatically along with the file that depends on them)
+		err = addDependencies(optionalMetadata, pack, index, false)
+		if err != nil {
+			return err
+		}
 	}
 
 	var file = version.Files[0]
@@ -375,7 +395,7 @@ func installVersion(project *modrinthApi.Project, version *modrinthApi.Version,
 	// TODO: handle optional/required resource pack files
 
 	// Create the metadata file
-	err := createFileMeta(project, version, file, pack, index)
+	err := createFileMeta(project, version, file, pack, index, false)
 	if err != nil {
 		return err
 	}
@@ -397,7 +417,170 @@ func installVersion(project *modrinthApi.Project, version *modrinthApi.Version,
 	return nil
 }
 
-func createFileMeta(project *modrinthApi.Project, version *modrinthApi.Version, file *modrinthApi.File, pack core.Pack, index *core.Index) error {
+// getLatestDependencyMetadata finds the latest version and file of each of the given dependency projects
+func getLatestDependencyMetadata(projectIDs []string, pack core.Pack) ([]depMetadataStore, error) {
+	if len(projectIDs) == 0 {
+		return nil, nil
+	}
+	projects, err := mrDefaultClient.Projects.GetMultiple(projectIDs)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving dependency data: %w", err)
+	}
+	var depMetadata []depMetadataStore
+	for _, project := range projects {
+		if project.ID == nil {
+			return nil, errors.New("failed to get dependency data: invalid response")
+		}
+		latestVersion, err := getLatestVersion(*project.ID, *project.Title, pack)
+		if err != nil {
+			fmt.Printf("Failed to get latest version of dependency %v: %v\n", *project.Title, err)
+			continue
+		}
+		var file = latestVersion.Files[0]
+		// Prefer the primary file
+		for _, v := range latestVersion.Files {
+			if *v.Primary {
+				file = v
+			}
+		}
+		depMetadata = append(depMetadata, depMetadataStore{
+			projectInfo: project,
+			versionInfo: latestVersion,
+			fileInfo:    file,
+		})
+	}
+	return depMetadata, nil
+}
+
+func addDependencies(depMetadata []depMetadataStore, pack core.Pack, index *core.Index, isDependency bool) error {
+	for _, v := range depMetadata {
+		err := createFileMeta(v.projectInfo, v.versionInfo, v.fileInfo, pack, index, isDependency)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Dependency \"%s\" successfully added! (%s)\n", *v.projectInfo.Title, *v.fileInfo.Filename)
+	}
+	return nil
+}
+
+// getDependencyProjects returns the projects of the dependencies of the given type, resolving version IDs to projects
+func getDependencyProjects(version *modrinthApi.Version, dependencyType string, isQuilt bool, mcVersion string) ([]*modrinthApi.Project, error) {
+	var projectIDs []string
+	var versionIDs []string
+	for _, dep := range version.Dependencies {
+		if dep.DependencyType == nil || *dep.DependencyType != dependencyType {
+			continue
+		}
+		if dep.ProjectID != nil {
+			projectIDs = append(projectIDs, mapDepOverride(*dep.ProjectID, isQuilt, mcVersion))
+		} else if dep.VersionID != nil {
+			versionIDs = append(versionIDs, *dep.VersionID)
+		}
+	}
+	if len(versionIDs) > 0 {
+		depVersions, err := mrDefaultClient.Versions.GetMultiple(versionIDs)
+		if err != nil {
+			return nil, fmt.Errorf("error retrieving dependency data: %w", err)
+		}
+		for _, v := range depVersions {
+			if v.ProjectID != nil {
+				projectIDs = append(projectIDs, mapDepOverride(*v.ProjectID, isQuilt, mcVersion))
+			}
+		}
+	}
+	if len(projectIDs) == 0 {
+		return nil, nil
+	}
+	slices.Sort(projectIDs)
+	projectIDs = slices.Compact(projectIDs)
+	projects, err := mrDefaultClient.Projects.GetMultiple(projectIDs)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving dependency data: %w", err)
+	}
+	return projects, nil
+}
+
+// checkIncompatibleDependencies warns if the version is marked as incompatible with any project already in the pack,
+// and asks the user whether to continue (refusing in non-interactive mode)
+func checkIncompatibleDependencies(version *modrinthApi.Version, installedProjects []string) error {
+	projects, err := getDependencyProjects(version, "incompatible", false, "")
+	if err != nil {
+		return err
+	}
+	var conflicts []string
+	for _, project := range projects {
+		if project.ID != nil && slices.Contains(installedProjects, *project.ID) {
+			conflicts = append(conflicts, *project.Title)
+		}
+	}
+	if len(conflicts) == 0 {
+		return nil
+	}
+
+	fmt.Println("Warning: this project is incompatible with the following projects already in the pack:")
+	for _, v := range conflicts {
+		fmt.Println(v)
+	}
+	if viper.GetBool("non-interactive") {
+		return errors.New("project is incompatible with projects already in the pack")
+	}
+	if !cmdshared.PromptYesNoDefaultNo("Would you like to add it anyway? [y/N]: ") {
+		return errors.New("cancelled due to incompatible projects")
+	}
+	return nil
+}
+
+// selectOptionalDependencies asks the user which optional dependencies (that aren't already in the pack) to add,
+// returning the selected project IDs
+func selectOptionalDependencies(version *modrinthApi.Version, installedProjects []string, isQuilt bool, mcVersion string) ([]string, error) {
+	projects, err := getDependencyProjects(version, "optional", isQuilt, mcVersion)
+	if err != nil {
+		return nil, err
+	}
+	var available []*modrinthApi.Project
+	for _, project := range projects {
+		if project.ID != nil && !slices.Contains(installedProjects, *project.ID) {
+			available = append(available, project)
+		}
+	}
+	if len(available) == 0 {
+		return nil, nil
+	}
+
+	var selected []string
+	if withOptionalFlag {
+		for _, project := range available {
+			selected = append(selected, *project.ID)
+		}
+		return selected, nil
+	}
+	if viper.GetBool("non-interactive") {
+		fmt.Println("Skipping optional dependencies (use --with-optional to add them)")
+		return nil, nil
+	}
+
+	menu := wmenu.NewMenu("Choose optional dependencies to add (separate numbers with spaces):")
+	menu.AllowMultiple()
+	menu.Option("None", nil, true, nil)
+	for _, project := range available {
+		menu.Option(*project.Title, project, false, nil)
+	}
+	menu.Action(func(menuRes []wmenu.Opt) error {
+		for _, v := range menuRes {
+			if project, ok := v.Value.(*modrinthApi.Project); ok {
+				selected = append(selected, *project.ID)
+			}
+		}
+		return nil
+	})
+	err = menu.Run()
+	if err != nil {
+		return nil, err
+	}
+	return selected, nil
+}
+
+func createFileMeta(project *modrinthApi.Project, version *modrinthApi.Version, file *modrinthApi.File, pack core.Pack, index *core.Index, isDependency bool) error {
 	updateMap := make(map[string]map[string]interface{})
 
 	var err error
@@ -429,7 +612,8 @@ func createFileMeta(project *modrinthApi.Project, version *modrinthApi.Version,
 			HashFormat: algorithm,
 			Hash:       hash,
 		},
-		Update: updateMap,
+		Update:     updateMap,
+		Dependency: isDependency,
 	}
 	var path string
 	folder := viper.GetString("meta-folder")
@@ -460,6 +644,7 @@ func createFileMeta(project *modrinthApi.Project, version *modrinthApi.Version,
 var projectIDFlag string
 var versionIDFlag string
 var versionFilenameFlag string
+var withOptionalFlag bool
 
 func init() {
 	modrinthCmd.AddCommand(installCmd)
@@ -467,4 +652,5 @@ func init() {
 	installCmd.Flags().StringVar(&projectIDFlag, "project-id", "", "The Modrinth project ID to use")
 	installCmd.Flags().StringVar(&versionIDFlag, "version-id", "", "The Modrinth version ID to use")
 	installCmd.Flags().StringVar(&versionFilenameFlag, "version-filename", "", "The Modrinth version filename to use")
+	installCmd.Flags().BoolVar(&withOptionalFlag, "with-optional", false, "Add all optional dependencies without asking")
 }
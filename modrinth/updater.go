@@ -4,6 +4,9 @@ import (
 	modrinthApi "codeberg.org/jmansfield/go-modrinth/modrinth"
 	"errors"
 	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
 
 	"github.com/mitchellh/mapstructure"
 	"github.com/packwiz/packwiz/core"
@@ -16,6 +19,14 @@ type mrUpdateData struct {
 	InstalledVersion string `mapstructure:"version"`
 }
 
+func (u mrUpdateData) GetProjectID() string {
+	return u.ProjectID
+}
+
+func (u mrUpdateData) GetVersionID() string {
+	return u.InstalledVersion
+}
+
 func (u mrUpdateData) ToMap() (map[string]interface{}, error) {
 	newMap := make(map[string]interface{})
 	err := mapstructure.Decode(u, &newMap)
@@ -74,6 +85,7 @@ func (u mrUpdater) CheckUpdate(mods []*core.Mod, pack core.Pack) ([]core.UpdateC
 		results[i] = core.UpdateCheck{
 			UpdateAvailable: true,
 			UpdateString:    mod.FileName + " -> " + *newFilename,
+			NewFileName:     *newFilename,
 			CachedState:     cachedStateStore{data.ProjectID, newVersion},
 		}
 	}
@@ -110,3 +122,292 @@ func (u mrUpdater) DoUpdate(mods []*core.Mod, cachedState []interface{}) error {
 
 	return nil
 }
+
+func (u mrUpdater) GetChangelogs(oldMod *core.Mod, newMod *core.Mod) ([]core.Changelog, error) {
+	oldRaw, ok := oldMod.GetParsedUpdateData("modrinth")
+	if !ok {
+		return nil, errors.New("failed to parse update metadata")
+	}
+	newRaw, ok := newMod.GetParsedUpdateData("modrinth")
+	if !ok {
+		return nil, errors.New("failed to parse update metadata")
+	}
+	oldData := oldRaw.(mrUpdateData)
+	newData := newRaw.(mrUpdateData)
+
+	oldVersion, err := mrDefaultClient.Versions.Get(oldData.InstalledVersion)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get version %s: %v", oldData.InstalledVersion, err)
+	}
+	newVersion, err := mrDefaultClient.Versions.Get(newData.InstalledVersion)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get version %s: %v", newData.InstalledVersion, err)
+	}
+
+	var versions []*modrinthApi.Version
+	if oldVersion.DatePublished != nil && newVersion.DatePublished != nil && newVersion.DatePublished.After(*oldVersion.DatePublished) {
+		// Include all the versions in between with the same loaders as the new version
+		allVersions, err := mrDefaultClient.Versions.ListVersions(newData.ProjectID, modrinthApi.ListVersionsOptions{
+			Loaders: newVersion.Loaders,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list versions of %s: %v", newData.ProjectID, err)
+		}
+		for _, v := range allVersions {
+			if v.DatePublished == nil || v.ID == nil || *v.ID == *newVersion.ID {
+				continue
+			}
+			if v.DatePublished.After(*oldVersion.DatePublished) && v.DatePublished.Before(*newVersion.DatePublished) {
+				versions = append(versions, v)
+			}
+		}
+		slices.SortFunc(versions, func(a, b *modrinthApi.Version) int {
+			return b.DatePublished.Compare(*a.DatePublished)
+		})
+	}
+	versions = append([]*modrinthApi.Version{newVersion}, versions...)
+
+	changelogs := make([]core.Changelog, len(versions))
+	for i, v := range versions {
+		if v.VersionNumber != nil {
+			changelogs[i].Version = *v.VersionNumber
+		} else if v.Name != nil {
+			changelogs[i].Version = *v.Name
+		}
+		if v.Changelog != nil {
+			changelogs[i].Text = *v.Changelog
+		}
+	}
+	return changelogs, nil
+}
+
+func (u mrUpdater) GetDependencyInfo(mods []*core.Mod, pack core.Pack) ([]core.DependencyInfo, error) {
+	results := make([]core.DependencyInfo, len(mods))
+	isQuilt := slices.Contains(pack.GetCompatibleLoaders(), "quilt")
+	mcVersion, err := pack.GetMCVersion()
+	if err != nil {
+		return nil, err
+	}
+
+	versionIDs := make([]string, 0, len(mods))
+	for _, mod := range mods {
+		rawData, ok := mod.GetParsedUpdateData("modrinth")
+		if !ok {
+			continue
+		}
+		versionIDs = append(versionIDs, rawData.(mrUpdateData).InstalledVersion)
+	}
+	versions, err := mrDefaultClient.Versions.GetMultiple(versionIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get version data: %v", err)
+	}
+	versionsByID := make(map[string]*modrinthApi.Version)
+	for _, v := range versions {
+		if v.ID != nil {
+			versionsByID[*v.ID] = v
+		}
+	}
+
+	// Look up the projects of dependencies specified by version ID
+	var depVersionIDs []string
+	for _, v := range versions {
+		for _, dep := range v.Dependencies {
+			if dep.ProjectID == nil && dep.VersionID != nil {
+				depVersionIDs = append(depVersionIDs, *dep.VersionID)
+			}
+		}
+	}
+	depVersionProjects := make(map[string]string)
+	if len(depVersionIDs) > 0 {
+		depVersions, err := mrDefaultClient.Versions.GetMultiple(depVersionIDs)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get dependency data: %v", err)
+		}
+		for _, v := range depVersions {
+			if v.ID != nil && v.ProjectID != nil {
+				depVersionProjects[*v.ID] = *v.ProjectID
+			}
+		}
+	}
+	getDepProjectID := func(dep *modrinthApi.Dependency) string {
+		if dep.ProjectID != nil {
+			return mapDepOverride(*dep.ProjectID, isQuilt, mcVersion)
+		} else if dep.VersionID != nil {
+			if id, ok := depVersionProjects[*dep.VersionID]; ok {
+				return mapDepOverride(id, isQuilt, mcVersion)
+			}
+		}
+		return ""
+	}
+
+	var depProjectIDs []string
+	for _, v := range versions {
+		for _, dep := range v.Dependencies {
+			if id := getDepProjectID(dep); id != "" {
+				depProjectIDs = append(depProjectIDs, id)
+			}
+		}
+	}
+	depNames := make(map[string]string)
+	if len(depProjectIDs) > 0 {
+		slices.Sort(depProjectIDs)
+		depProjectIDs = slices.Compact(depProjectIDs)
+		depProjects, err := mrDefaultClient.Projects.GetMultiple(depProjectIDs)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get dependency data: %v", err)
+		}
+		for _, v := range depProjects {
+			if v.ID != nil && v.Title != nil {
+				depNames[*v.ID] = *v.Title
+			}
+		}
+	}
+
+	for i, mod := range mods {
+		rawData, ok := mod.GetParsedUpdateData("modrinth")
+		if !ok {
+			results[i] = core.DependencyInfo{Error: errors.New("failed to parse update metadata")}
+			continue
+		}
+		version, ok := versionsByID[rawData.(mrUpdateData).InstalledVersion]
+		if !ok {
+			results[i] = core.DependencyInfo{Error: errors.New("failed to get version data")}
+			continue
+		}
+
+		results[i].Loaders = version.Loaders
+		results[i].GameVersions = version.GameVersions
+		for _, dep := range version.Dependencies {
+			id := getDepProjectID(dep)
+			if id == "" || dep.DependencyType == nil {
+				continue
+			}
+			name, ok := depNames[id]
+			if !ok {
+				name = id
+			}
+			switch *dep.DependencyType {
+			case "required":
+				results[i].Required = append(results[i].Required, core.Dependency{ProjectID: id, Name: name})
+			case "incompatible":
+				results[i].Incompatible = append(results[i].Incompatible, core.Dependency{ProjectID: id, Name: name})
+			}
+		}
+	}
+
+	return results, nil
+}
+
+func (u mrUpdater) GetMatchHashFormats() []string {
+	return []string{"sha512"}
+}
+
+func (u mrUpdater) MatchFiles(mods []*core.Mod, hashes []map[string]string) ([]*core.FileMatch, error) {
+	results := make([]*core.FileMatch, len(mods))
+	hashList := make([]string, 0, len(mods))
+	for _, v := range hashes {
+		if hash, ok := v["sha512"]; ok {
+			hashList = append(hashList, hash)
+		}
+	}
+	if len(hashList) == 0 {
+		return results, nil
+	}
+	versions, err := mrDefaultClient.VersionFiles.GetFromHashes(hashList, "sha512")
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up hashes: %v", err)
+	}
+	versionsByHash := make(map[string]*modrinthApi.Version)
+	for hash, version := range versions {
+		versionsByHash[strings.ToLower(hash)] = version
+	}
+
+	for i := range mods {
+		hash := strings.ToLower(hashes[i]["sha512"])
+		version, ok := versionsByHash[hash]
+		if !ok || version.ProjectID == nil || version.ID == nil {
+			continue
+		}
+		for _, file := range version.Files {
+			if file.URL != nil && strings.EqualFold(file.Hashes["sha512"], hash) {
+				updateData, err := mrUpdateData{
+					ProjectID:        *version.ProjectID,
+					InstalledVersion: *version.ID,
+				}.ToMap()
+				if err != nil {
+					return nil, err
+				}
+				results[i] = &core.FileMatch{UpdateData: updateData, DownloadURL: *file.URL}
+				break
+			}
+		}
+	}
+	return results, nil
+}
+
+func (u mrUpdater) GetMigrateHashFormats() []string {
+	return u.GetMatchHashFormats()
+}
+
+func (u mrUpdater) FindMigrationFile(mod *core.Mod, project string, hashes map[string]string, pack core.Pack) (core.MigrationFile, error) {
+	matches, err := u.MatchFiles([]*core.Mod{mod}, []map[string]string{hashes})
+	if err != nil {
+		return core.MigrationFile{}, err
+	}
+	if matches[0] != nil {
+		return core.MigrationFile{
+			FileName: mod.FileName,
+			Download: core.ModDownload{
+				URL:        matches[0].DownloadURL,
+				HashFormat: "sha512",
+				Hash:       hashes["sha512"],
+			},
+			UpdateData: matches[0].UpdateData,
+			Exact:      true,
+		}, nil
+	}
+
+	// Metadata files default to being named after the project slug
+	if project == "" {
+		project = strings.TrimSuffix(filepath.Base(mod.GetFilePath()), core.MetaExtension)
+	}
+	projectData, err := mrDefaultClient.Projects.Get(project)
+	if err != nil {
+		return core.MigrationFile{}, fmt.Errorf("failed to find project %s: %v", project, err)
+	}
+	version, err := getLatestVersion(*projectData.ID, mod.Name, pack)
+	if err != nil {
+		return core.MigrationFile{}, err
+	}
+	if len(version.Files) == 0 {
+		return core.MigrationFile{}, errors.New("version doesn't have any files")
+	}
+	file := version.Files[0]
+	// Prefer the primary file
+	for _, v := range version.Files {
+		if *v.Primary {
+			file = v
+		}
+	}
+	algorithm, hash := getBestHash(file)
+	if algorithm == "" {
+		return core.MigrationFile{}, errors.New("file for project " + mod.Name + " doesn't have a valid hash")
+	}
+	updateData, err := mrUpdateData{
+		ProjectID:        *projectData.ID,
+		InstalledVersion: *version.ID,
+	}.ToMap()
+	if err != nil {
+		return core.MigrationFile{}, err
+	}
+	return core.MigrationFile{
+		FileName: *file.Filename,
+		Download: core.ModDownload{
+			URL:        *file.URL,
+			HashFormat: algorithm,
+			Hash:       hash,
+		},
+		UpdateData: updateData,
+		Version:    *version.VersionNumber,
+	}, nil
+}
@@ -0,0 +1,83 @@
+package modrinth
+
+import (
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/packwiz/packwiz/core"
+)
+
+func TestFindMigrationFile(t *testing.T) {
+	httpmock.Activate(t)
+
+	versions, err := httpmock.NewJsonResponder(200, map[string]interface{}{
+		"exact": map[string]interface{}{
+			"id":         "V1",
+			"project_id": "P1",
+			"files": []map[string]interface{}{{
+				"url":    "https://cdn.modrinth.com/data/P1/versions/V1/example-1.0.jar",
+				"hashes": map[string]string{"sha512": "exact"},
+			}},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	httpmock.RegisterResponder("POST", "https://api.modrinth.com/v2/version_files", versions)
+	project, err := httpmock.NewJsonResponder(200, map[string]interface{}{"id": "P1", "slug": "example"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	httpmock.RegisterResponder("GET", "https://api.modrinth.com/v2/project/example", project)
+	projectVersions, err := httpmock.NewJsonResponder(200, []map[string]interface{}{{
+		"id":             "V2",
+		"project_id":     "P1",
+		"version_number": "1.1",
+		"game_versions":  []string{"1.20.1"},
+		"loaders":        []string{"fabric"},
+		"date_published": "2024-01-01T00:00:00Z",
+		"files": []map[string]interface{}{{
+			"url":      "https://cdn.modrinth.com/data/P1/versions/V2/example-1.1.jar",
+			"filename": "example-1.1.jar",
+			"primary":  true,
+			"hashes":   map[string]string{"sha1": "sha1-hash", "sha512": "sha512-hash"},
+		}},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	httpmock.RegisterResponder("GET", "https://api.modrinth.com/v2/project/P1/version", projectVersions)
+
+	pack := core.Pack{Versions: map[string]string{"minecraft": "1.20.1", "fabric": "0.15.0"}}
+	mod := &core.Mod{Name: "Example", FileName: "example-1.0.jar"}
+	mod.SetMetaPath("mods/example" + core.MetaExtension)
+
+	file, err := mrUpdater{}.FindMigrationFile(mod, "", map[string]string{"sha512": "exact"}, pack)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !file.Exact || file.FileName != "example-1.0.jar" || file.Download.Hash != "exact" {
+		t.Errorf("Expected the same file to be found, got %+v", file)
+	}
+	if file.UpdateData["version"] != "V1" {
+		t.Errorf("Expected update metadata for version V1, got %v", file.UpdateData)
+	}
+
+	// Files that can't be found by their hash fall back to the latest version of the project named by the metadata file
+	file, err = mrUpdater{}.FindMigrationFile(mod, "", map[string]string{"sha512": "unknown"}, pack)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if file.Exact {
+		t.Error("Expected a different file to not be marked as exact")
+	}
+	if file.FileName != "example-1.1.jar" || file.Version != "1.1" {
+		t.Errorf("Expected the latest version to be used, got %+v", file)
+	}
+	if file.Download.HashFormat != "sha512" || file.Download.Hash != "sha512-hash" {
+		t.Errorf("Expected the sha512 hash of the latest version, got %v", file.Download)
+	}
+	if file.UpdateData["mod-id"] != "P1" || file.UpdateData["version"] != "V2" {
+		t.Errorf("Expected update metadata for version V2, got %v", file.UpdateData)
+	}
+}
@@ -0,0 +1,193 @@
+package releases
+
+import (
+	"errors"
+	"fmt"
+	"math/bits"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/dlclark/regexp2"
+	"github.com/packwiz/packwiz/core"
+	"gopkg.in/dixonwille/wmenu.v4"
+)
+
+// matchAssets returns the assets of a release with names matching the given regex
+func matchAssets(release Release, regex string) ([]Asset, error) {
+	if len(release.Assets) == 0 {
+		return nil, errors.New("release doesn't have any assets attached")
+	}
+	expr, err := regexp2.Compile(regex, 0)
+	if err != nil {
+		return nil, fmt.Errorf("invalid regex: %v", err)
+	}
+
+	var files []Asset
+	for _, v := range release.Assets {
+		if bl, _ := expr.MatchString(v.Name); bl {
+			files = append(files, v)
+		}
+	}
+	if len(files) == 0 {
+		return nil, errors.New("release doesn't have any assets matching regex")
+	}
+	return files, nil
+}
+
+// MatchAsset returns the single asset of a release with a name matching the given regex
+func MatchAsset(release Release, regex string) (Asset, error) {
+	files, err := matchAssets(release, regex)
+	if err != nil {
+		return Asset{}, err
+	}
+	if len(files) > 1 {
+		return Asset{}, fmt.Errorf("release has more than one asset matching regex: %s", assetNames(files))
+	}
+	return files[0], nil
+}
+
+func assetNames(assets []Asset) string {
+	names := make([]string, len(assets))
+	for i, v := range assets {
+		names[i] = v.Name
+	}
+	return strings.Join(names, ", ")
+}
+
+// versionRegex matches version numbers within asset names (e.g. 1.2.3 in mod-1.2.3-client.jar)
+var versionRegex = regexp.MustCompile(`\d+(?:\.\d+)*`)
+
+// assetRegex returns a regex that matches the given asset name, with the version numbers in the name allowed to change
+// (except those with their bit set in literal, counting from the start of the name), so that the same asset can be
+// found in later releases
+func assetRegex(name string, literal uint) string {
+	var sb strings.Builder
+	sb.WriteString("^")
+	last := 0
+	for i, loc := range versionRegex.FindAllStringIndex(name, -1) {
+		if literal&(1<<i) != 0 {
+			continue
+		}
+		sb.WriteString(regexp.QuoteMeta(name[last:loc[0]]))
+		sb.WriteString(versionRegex.String())
+		last = loc[1]
+	}
+	sb.WriteString(regexp.QuoteMeta(name[last:]))
+	sb.WriteString("$")
+	return sb.String()
+}
+
+// uniqueAssetRegex returns a regex that matches only the given asset of a release, which can still match the same asset
+// in later releases. Version numbers are kept in the regex (fewest first) when they are needed to tell assets apart,
+// e.g. when a release has an asset for each Minecraft version, but at least one must be allowed to change.
+func uniqueAssetRegex(release Release, name string) (string, error) {
+	count := len(versionRegex.FindAllStringIndex(name, -1))
+	for n := 0; n == 0 || n < count; n++ {
+		for literal := uint(0); literal < 1<<count; literal++ {
+			if bits.OnesCount(literal) != n {
+				continue
+			}
+			regex := assetRegex(name, literal)
+			if matched, err := matchAssets(release, regex); err == nil && len(matched) == 1 {
+				return regex, nil
+			}
+		}
+	}
+	return "", fmt.Errorf("no regex could be generated to match only %s in later releases; use --regex to add it separately", name)
+}
+
+// assetStem returns the name of an asset without its extension or version numbers (e.g. mod-client for
+// mod-1.2.3+mc1.20.1-client.jar)
+func assetStem(name string) string {
+	base := strings.TrimSuffix(name, filepath.Ext(name))
+	var parts []string
+	for _, v := range strings.FieldsFunc(versionRegex.ReplaceAllString(base, ""), func(r rune) bool {
+		return strings.ContainsRune("-_+. ", r)
+	}) {
+		// Prefixes of version numbers, such as v1.2.3 or mc1.20.1
+		if v == "v" || v == "mc" {
+			continue
+		}
+		parts = append(parts, v)
+	}
+	if len(parts) == 0 {
+		return base
+	}
+	return strings.Join(parts, "-")
+}
+
+// assetMetaName returns the name of the metadata file (without the extension) for an asset that is added alongside
+// others, which isn't one of the names already used
+func assetMetaName(name string, used map[string]bool) string {
+	metaName := core.SlugifyName(assetStem(name))
+	if used[metaName] {
+		metaName = core.SlugifyName(strings.TrimSuffix(name, filepath.Ext(name)))
+	}
+	// Names can still collide (e.g. when slugifying removes the difference), so add a number to make them unique
+	baseName := metaName
+	for n := 2; used[metaName]; n++ {
+		metaName = baseName + "-" + strconv.Itoa(n)
+	}
+	return metaName
+}
+
+// guessAssetSide guesses the side an asset should be installed on from its name
+func guessAssetSide(name string) string {
+	lower := strings.ToLower(name)
+	client := strings.Contains(lower, "client")
+	server := strings.Contains(lower, "server")
+	if client && !server {
+		return core.ClientSide
+	}
+	if server && !client {
+		return core.ServerSide
+	}
+	return core.UniversalSide
+}
+
+// chooseAssets asks the user which of the given assets to add (defaulting to all of them)
+func chooseAssets(files []Asset) ([]Asset, error) {
+	var selected []Asset
+	menu := wmenu.NewMenu("Choose assets to add (separate numbers with spaces, defaults to all):")
+	menu.AllowMultiple()
+	for _, v := range files {
+		menu.Option(v.Name, v, true, nil)
+	}
+	menu.Action(func(menuRes []wmenu.Opt) error {
+		for _, v := range menuRes {
+			if asset, ok := v.Value.(Asset); ok {
+				selected = append(selected, asset)
+			}
+		}
+		return nil
+	})
+	err := menu.Run()
+	if err != nil {
+		return nil, err
+	}
+	return selected, nil
+}
+
+// chooseSide asks the user which side an asset should be installed on
+func chooseSide(file Asset, guess string) (string, error) {
+	side := guess
+	menu := wmenu.NewMenu("Choose the side to install " + file.Name + " on:")
+	for _, v := range []string{core.UniversalSide, core.ClientSide, core.ServerSide} {
+		menu.Option(v, v, v == guess, nil)
+	}
+	menu.Action(func(menuRes []wmenu.Opt) error {
+		if len(menuRes) == 1 {
+			if s, ok := menuRes[0].Value.(string); ok {
+				side = s
+			}
+		}
+		return nil
+	})
+	err := menu.Run()
+	if err != nil {
+		return "", err
+	}
+	return side, nil
+}
@@ -0,0 +1,120 @@
+package releases
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/packwiz/packwiz/core"
+)
+
+func TestAssetRegex(t *testing.T) {
+	tests := []struct {
+		name    string
+		matches []string
+		rejects []string
+	}{
+		{"mod-1.2.3.jar", []string{"mod-1.2.3.jar", "mod-1.3.0.jar", "mod-2.jar"}, []string{"mod-1.2.3-sources.jar", "modx1.2.3.jar", "other-1.2.3.jar"}},
+		{"mod-fabric-1.0+mc1.20.1.jar", []string{"mod-fabric-1.1+mc1.20.4.jar"}, []string{"mod-forge-1.1+mc1.20.4.jar", "mod-fabric-1.1-mc1.20.4.jar"}},
+		{"mod.jar", []string{"mod.jar"}, []string{"mod-1.jar"}},
+	}
+	for _, tt := range tests {
+		expr := regexp.MustCompile(assetRegex(tt.name, 0))
+		for _, v := range tt.matches {
+			if !expr.MatchString(v) {
+				t.Errorf("assetRegex(%q) = %s, expected to match %s", tt.name, expr, v)
+			}
+		}
+		for _, v := range tt.rejects {
+			if expr.MatchString(v) {
+				t.Errorf("assetRegex(%q) = %s, expected not to match %s", tt.name, expr, v)
+			}
+		}
+	}
+}
+
+func TestUniqueAssetRegex(t *testing.T) {
+	release := Release{Assets: []Asset{
+		{Name: "mod-1.0-client.jar"},
+		{Name: "mod-1.0-server.jar"},
+		{Name: "mod-1.0+mc1.20.1.jar"},
+		{Name: "mod-1.0+mc1.21.jar"},
+		{Name: "other-1.0.jar"},
+		{Name: "other-1.1.jar"},
+	}}
+	tests := []struct {
+		name    string
+		want    string
+		wantErr bool
+	}{
+		{"mod-1.0-client.jar", `^mod-\d+(?:\.\d+)*-client\.jar$`, false},
+		// The Minecraft version is kept, as it is the only difference between the assets
+		{"mod-1.0+mc1.21.jar", `^mod-\d+(?:\.\d+)*\+mc1\.21\.jar$`, false},
+		// The assets can only be told apart by keeping every version number, so the asset wouldn't be found in later
+		// releases
+		{"other-1.0.jar", "", true},
+	}
+	for _, tt := range tests {
+		got, err := uniqueAssetRegex(release, tt.name)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("uniqueAssetRegex(%q) error = %v, wantErr %v", tt.name, err, tt.wantErr)
+		}
+		if got != tt.want {
+			t.Errorf("uniqueAssetRegex(%q) = %s, want %s", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestAssetStem(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"mod-1.2.3.jar", "mod"},
+		{"mod-1.2.3+mc1.20.1-client.jar", "mod-client"},
+		{"mod_v1.2_server.jar", "mod-server"},
+		{"Mod Fabric 1.0.jar", "Mod-Fabric"},
+		{"1.2.3.jar", "1.2.3"},
+	}
+	for _, tt := range tests {
+		if got := assetStem(tt.name); got != tt.want {
+			t.Errorf("assetStem(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestGuessAssetSide(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"mod-1.0-client.jar", core.ClientSide},
+		{"mod-SERVER-1.0.jar", core.ServerSide},
+		{"mod-1.0.jar", core.UniversalSide},
+		{"mod-client-server-1.0.jar", core.UniversalSide},
+	}
+	for _, tt := range tests {
+		if got := guessAssetSide(tt.name); got != tt.want {
+			t.Errorf("guessAssetSide(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestAssetMetaName(t *testing.T) {
+	used := make(map[string]bool)
+	// The second and third assets have the same stem, and the third slugifies to the same name as the second
+	for _, tt := range []struct {
+		name string
+		want string
+	}{
+		{"mod-1.0-client.jar", "mod-client"},
+		{"mod-1.0-server.jar", "mod-server"},
+		{"mod-server-1.0.jar", "mod-server-1-0"},
+		{"Mod-Server-1.0.jar", "mod-server-1-0-2"},
+	} {
+		got := assetMetaName(tt.name, used)
+		if got != tt.want {
+			t.Errorf("assetMetaName(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+		used[got] = true
+	}
+}
@@ -0,0 +1,290 @@
+package releases
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"slices"
+
+	"github.com/dlclark/regexp2"
+	"github.com/packwiz/packwiz/core"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/unascribed/FlexVer/go/flexver"
+)
+
+// DefaultRegex will match any asset with a name that does *not* end with:
+// - "-api.jar"
+// - "-dev.jar"
+// - "-dev-preshadow.jar"
+// - "-sources.jar"
+// In most cases, this will only match one asset.
+// TODO: Hopefully.
+const DefaultRegex = `^.+(?<!-api|-dev|-dev-preshadow|-sources)\.jar$`
+
+// The channels that releases can be tracked from:
+// - "release" uses the latest release, excluding pre-releases
+// - "prerelease" uses the latest release, including pre-releases
+// - "tag-pattern" uses the release with the highest tag (using FlexVer ordering) matching a regex, including pre-releases
+// When no channel is set (the default, and in metadata files from before channels were added), the latest release is
+// used including pre-releases, which is the same as "prerelease"
+const (
+	channelRelease    = "release"
+	channelPrerelease = "prerelease"
+	channelTagPattern = "tag-pattern"
+)
+
+// InstallOptions stores the flags of the add command of a host
+type InstallOptions struct {
+	Branch     string
+	Regex      string
+	Channel    string
+	TagPattern string
+	AllAssets  bool
+}
+
+// AddFlags adds the flags for these options to the add command of a host; the branch flag is only added for hosts
+// that provide the branch of each release
+func (o *InstallOptions) AddFlags(cmd *cobra.Command, branches bool) {
+	if branches {
+		cmd.Flags().StringVar(&o.Branch, "branch", "", "The repository branch to retrieve releases for")
+	}
+	cmd.Flags().StringVar(&o.Regex, "regex", "", "The regular expression to match releases against")
+	cmd.Flags().StringVar(&o.Channel, "channel", "", "The releases to track: release, prerelease, or tag-pattern (defaults to the latest release, including pre-releases)")
+	cmd.Flags().StringVar(&o.TagPattern, "tag-pattern", "", "The regular expression to match tags against, for the tag-pattern channel (implies --channel tag-pattern)")
+	cmd.Flags().BoolVar(&o.AllAssets, "all-assets", false, "Add every asset matching the regex as a separate file, rather than asking which to add")
+}
+
+func validateChannel(channel string, tagPattern string) error {
+	switch channel {
+	case "", channelRelease, channelPrerelease:
+		if tagPattern != "" {
+			return errors.New("a tag pattern can only be used with the tag-pattern channel")
+		}
+	case channelTagPattern:
+		if tagPattern == "" {
+			return errors.New("the tag-pattern channel requires a tag pattern")
+		}
+		_, err := regexp2.Compile(tagPattern, 0)
+		if err != nil {
+			return fmt.Errorf("invalid tag pattern: %v", err)
+		}
+	default:
+		return fmt.Errorf("unknown channel %s; must be one of release, prerelease or tag-pattern", channel)
+	}
+	return nil
+}
+
+// GetLatestRelease returns the latest release of a project from the given branch (if not empty) and channel
+func (u Updater) GetLatestRelease(apiURL string, slug string, branch string, channel string, tagPattern string) (Release, error) {
+	var release Release
+
+	err := validateChannel(channel, tagPattern)
+	if err != nil {
+		return release, err
+	}
+
+	releases, err := u.Host.GetReleases(apiURL, slug, 1)
+	if err != nil {
+		return release, err
+	}
+
+	var tagExpr *regexp2.Regexp
+	if channel == channelTagPattern {
+		tagExpr = regexp2.MustCompile(tagPattern, 0)
+	}
+	found := false
+	// Releases are listed newest first
+	for _, r := range releases {
+		if r.Draft || (branch != "" && r.Branch != branch) {
+			continue
+		}
+		switch channel {
+		case "", channelPrerelease:
+			return r, nil
+		case channelTagPattern:
+			if bl, _ := tagExpr.MatchString(r.TagName); bl && (!found || flexver.Less(release.TagName, r.TagName)) {
+				release = r
+				found = true
+			}
+		case channelRelease:
+			if !r.Prerelease {
+				return r, nil
+			}
+		}
+	}
+	if found {
+		return release, nil
+	}
+
+	if branch != "" {
+		return release, fmt.Errorf("failed to find %s for branch %v", describeChannel(channel, tagPattern), branch)
+	}
+	return release, fmt.Errorf("failed to find %s", describeChannel(channel, tagPattern))
+}
+
+func describeChannel(channel string, tagPattern string) string {
+	switch channel {
+	case "", channelPrerelease:
+		return "release or pre-release"
+	case channelTagPattern:
+		return "release with a tag matching " + tagPattern
+	default:
+		return "release"
+	}
+}
+
+// Install adds the assets of the latest release of a project to the pack
+func (u Updater) Install(apiURL string, project Project, opts InstallOptions, pack core.Pack) error {
+	regex := DefaultRegex
+	if opts.Regex != "" {
+		regex = opts.Regex
+	}
+	channel := opts.Channel
+	if channel == "" && opts.TagPattern != "" {
+		channel = channelTagPattern
+	}
+	err := validateChannel(channel, opts.TagPattern)
+	if err != nil {
+		return err
+	}
+
+	latestRelease, err := u.GetLatestRelease(apiURL, project.Slug, opts.Branch, channel, opts.TagPattern)
+	if err != nil {
+		return fmt.Errorf("failed to get latest release: %v", err)
+	}
+
+	files, err := matchAssets(latestRelease, regex)
+	if err != nil {
+		return err
+	}
+
+	// When the regex matches more than one asset, each chosen asset is added as a separate file with its own regex and
+	// side, all tracking the same release
+	multiple := len(files) > 1
+	if multiple {
+		if opts.AllAssets {
+			fmt.Printf("Adding all %d assets matching regex\n", len(files))
+		} else if viper.GetBool("non-interactive") {
+			return fmt.Errorf("release has more than one asset matching regex: %s (use --all-assets to add all of them, or --regex to choose one)", assetNames(files))
+		} else {
+			files, err = chooseAssets(files)
+			if err != nil {
+				return err
+			}
+			if len(files) == 0 {
+				return errors.New("no assets were chosen")
+			}
+		}
+	}
+
+	index, err := pack.LoadIndex()
+	if err != nil {
+		return err
+	}
+
+	folder := viper.GetString("meta-folder")
+	if folder == "" {
+		folder = "mods"
+	}
+	// Each asset is added with its own regex and side; the regexes of the other assets are stored with each of them, so
+	// they are updated together
+	regexes := make([]string, len(files))
+	sides := make([]string, len(files))
+	for i, file := range files {
+		regexes[i] = regex
+		sides[i] = core.UniversalSide
+		if !multiple {
+			continue
+		}
+		regexes[i], err = uniqueAssetRegex(latestRelease, file.Name)
+		if err != nil {
+			return err
+		}
+		sides[i] = guessAssetSide(file.Name)
+		if !opts.AllAssets && !viper.GetBool("non-interactive") {
+			sides[i], err = chooseSide(file, sides[i])
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	usedNames := make(map[string]bool)
+	// Files are only named after their assets when more than one is added
+	separateNames := len(files) > 1
+	for i, file := range files {
+		name := project.Name
+		metaName := core.SlugifyName(project.Name)
+		var siblings []string
+		if separateNames {
+			name = project.Name + " (" + assetStem(file.Name) + ")"
+			metaName = assetMetaName(file.Name, usedNames)
+			usedNames[metaName] = true
+			siblings = slices.Delete(slices.Clone(regexes), i, i+1)
+		}
+
+		// Install the file
+		fmt.Printf("Installing %s from release %s\n", file.Name, latestRelease.TagName)
+
+		updateMap := make(map[string]map[string]interface{})
+		updateMap[u.Name], err = UpdateData{
+			Slug:   project.Slug,
+			ApiURL: apiURL,
+			Tag:    latestRelease.TagName,
+			// The branch is only recorded when it was specified, so that updates aren't limited to the branch of this release
+			Branch:     opts.Branch,
+			Regex:      regexes[i],
+			Channel:    channel,
+			TagPattern: opts.TagPattern,
+			Siblings:   siblings,
+		}.ToMap()
+		if err != nil {
+			return err
+		}
+
+		hash, err := HashAsset(u.Host, file)
+		if err != nil {
+			return err
+		}
+
+		modMeta := core.Mod{
+			Name:     name,
+			FileName: file.Name,
+			Side:     sides[i],
+			Download: core.ModDownload{
+				URL:        file.DownloadURL,
+				HashFormat: "sha256",
+				Hash:       hash,
+			},
+			Update: updateMap,
+		}
+		path := modMeta.SetMetaPath(filepath.Join(viper.GetString("meta-folder-base"), folder, metaName+core.MetaExtension))
+
+		// If the file already exists, this will overwrite it!!!
+		// TODO: Should this be improved?
+		// Current strategy is to go ahead and do stuff without asking, with the assumption that you are using
+		// VCS anyway.
+
+		format, hash, err := modMeta.Write()
+		if err != nil {
+			return err
+		}
+
+		err = index.RefreshFileWithHash(path, format, hash, true)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Project \"%s\" successfully added! (%s)\n", name, file.Name)
+	}
+
+	err = index.Write()
+	if err != nil {
+		return err
+	}
+	err = pack.UpdateIndexHash()
+	if err != nil {
+		return err
+	}
+	return pack.Write()
+}
@@ -0,0 +1,101 @@
+package releases
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/mitchellh/mapstructure"
+	"github.com/packwiz/packwiz/core"
+)
+
+// Host is the API of a site that projects publish releases on (e.g. GitHub), used to add and update files from their
+// release assets
+type Host interface {
+	// GetProject returns the project with the given slug, using the API at apiURL (for hosts with multiple instances)
+	GetProject(apiURL string, slug string) (Project, error)
+	// GetReleases returns a page of the releases of a project (starting from 1), newest first; there are no more
+	// releases when an empty page is returned
+	GetReleases(apiURL string, slug string, page int) ([]Release, error)
+	// GetAsset starts downloading a release asset
+	GetAsset(asset Asset) (*http.Response, error)
+}
+
+type Project struct {
+	Name string // "hello_world"
+	Slug string // "owner/hello_world"
+}
+
+type Release struct {
+	TagName string
+	Name    string
+	Body    string
+	// Branch is the branch the release was made from, or empty if the host doesn't provide it
+	Branch string
+	// Draft is true for releases that haven't been published yet
+	Draft      bool
+	Prerelease bool
+	Assets     []Asset
+}
+
+type Asset struct {
+	Name        string
+	DownloadURL string
+}
+
+type UpdateData struct {
+	Slug string `mapstructure:"slug"`
+	// ApiURL is the API of the instance the project is on, for hosts with multiple instances
+	ApiURL string `mapstructure:"api-url,omitempty"`
+	Tag    string `mapstructure:"tag"`
+	Branch string `mapstructure:"branch,omitempty"`
+	Regex  string `mapstructure:"regex"`
+	// Channel is the channel to track releases from (release, prerelease or tag-pattern); empty is equivalent to prerelease
+	Channel    string `mapstructure:"channel,omitempty"`
+	TagPattern string `mapstructure:"tag-pattern,omitempty"`
+	// Siblings are the regexes of the other assets of the same release that were added with this file, which must be
+	// updated at the same time so that they stay on the same release
+	Siblings []string `mapstructure:"siblings,omitempty"`
+}
+
+func (u UpdateData) GetProjectID() string {
+	if u.ApiURL == "" {
+		return u.Slug
+	}
+	return u.ApiURL + "/" + u.Slug
+}
+
+func (u UpdateData) GetVersionID() string {
+	return u.Tag
+}
+
+func (u UpdateData) ToMap() (map[string]interface{}, error) {
+	newMap := make(map[string]interface{})
+	err := mapstructure.Decode(u, &newMap)
+	return newMap, err
+}
+
+// HashAsset downloads a release asset, returning its SHA-256 hash
+func HashAsset(host Host, asset Asset) (string, error) {
+	mainHasher, err := core.GetHashImpl("sha256")
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := host.GetAsset(asset)
+	if err != nil {
+		return "", err
+	}
+
+	defer resp.Body.Close()
+	if resp.StatusCode != 200 {
+		return "", fmt.Errorf("failed to download: unexpected response status: %v", resp.Status)
+	}
+
+	_, err = io.Copy(mainHasher, resp.Body)
+	if err != nil {
+		return "", err
+	}
+
+	return mainHasher.HashToString(mainHasher.Sum(nil)), nil
+}
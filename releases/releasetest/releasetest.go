@@ -0,0 +1,248 @@
+// Package releasetest provides utilities for testing release hosts, using httpmock to serve their APIs
+package releasetest
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/packwiz/packwiz/core"
+	"github.com/packwiz/packwiz/releases"
+	"github.com/spf13/viper"
+)
+
+// NewPack creates an empty pack in a temporary directory, which is used as the pack file until the test finishes
+func NewPack(t *testing.T) core.Pack {
+	t.Helper()
+	dir := t.TempDir()
+	packFile := filepath.Join(dir, "pack.toml")
+	err := os.WriteFile(packFile, []byte(`name = "Test"
+pack-format = "packwiz:1.2.0"
+[index]
+file = "index.toml"
+hash-format = "sha256"
+hash = ""
+[versions]
+minecraft = "1.20.1"
+`), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "index.toml"), []byte(`hash-format = "sha256"`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	viper.Set("pack-file", packFile)
+	viper.Set("meta-folder-base", dir)
+	t.Cleanup(func() {
+		viper.Set("pack-file", nil)
+		viper.Set("meta-folder-base", nil)
+	})
+	pack, err := core.LoadPack()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pack
+}
+
+// Releases stores the releases of a project, each with one asset named mod-<tag>.jar downloaded from FilesURL
+type Releases struct {
+	FilesURL string
+	mu       sync.Mutex
+	tags     []string
+}
+
+// NewReleases creates an empty list of releases, and registers a responder for downloading their assets (containing
+// the path of the asset)
+func NewReleases(filesURL string) *Releases {
+	httpmock.RegisterResponder("GET", "=~^"+regexp.QuoteMeta(filesURL)+"/", func(req *http.Request) (*http.Response, error) {
+		return httpmock.NewStringResponse(200, req.URL.Path), nil
+	})
+	return &Releases{FilesURL: filesURL}
+}
+
+// Publish adds a new release with the given tag
+func (r *Releases) Publish(tag string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	// Releases are listed newest first
+	r.tags = append([]string{tag}, r.tags...)
+}
+
+// AssetName returns the name of the asset of a release
+func (r *Releases) AssetName(tag string) string {
+	return "mod-" + tag + ".jar"
+}
+
+// AssetURL returns the download URL of the asset of a release
+func (r *Releases) AssetURL(tag string) string {
+	return r.FilesURL + "/" + r.AssetName(tag)
+}
+
+// Responder returns an httpmock responder for the releases API of a host, which reads the page number from the page
+// query parameter and the number of releases on each page from sizeParam, and encodes the tags of the releases on the
+// page as JSON using encode
+func (r *Releases) Responder(sizeParam string, encode func(tags []string) interface{}) httpmock.Responder {
+	return func(req *http.Request) (*http.Response, error) {
+		page, err := strconv.Atoi(req.URL.Query().Get("page"))
+		if err != nil || page < 1 {
+			return httpmock.NewStringResponse(400, "invalid page"), nil
+		}
+		size, err := strconv.Atoi(req.URL.Query().Get(sizeParam))
+		if err != nil || size < 1 {
+			return httpmock.NewStringResponse(400, "invalid page size"), nil
+		}
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		tags := []string{}
+		if start := (page - 1) * size; start < len(r.tags) {
+			tags = r.tags[start:min(start+size, len(r.tags))]
+		}
+		return httpmock.NewJsonResponse(200, encode(tags))
+	}
+}
+
+// Tokens records the tokens sent in a header with requests to httpmock responders
+type Tokens struct {
+	Header string
+	// Prefix is removed from the value of the header (e.g. "token " for "Authorization: token ...")
+	Prefix string
+	mu     sync.Mutex
+	values []string
+}
+
+// Record wraps a responder to record the tokens sent to it
+func (t *Tokens) Record(responder httpmock.Responder) httpmock.Responder {
+	return func(req *http.Request) (*http.Response, error) {
+		t.mu.Lock()
+		t.values = append(t.values, strings.TrimPrefix(req.Header.Get(t.Header), t.Prefix))
+		t.mu.Unlock()
+		return responder(req)
+	}
+}
+
+// Sent returns the tokens sent since it was last called
+func (t *Tokens) Sent() []string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	values := t.values
+	t.values = nil
+	return values
+}
+
+// CheckInstallAndUpdate adds a project named Mod from a host, then checks that it is updated to new releases and that
+// changelogs are retrieved across pages of releases
+func CheckInstallAndUpdate(t *testing.T, u releases.Updater, apiURL string, slug string, r *Releases) {
+	t.Helper()
+	pack := NewPack(t)
+	r.Publish("1.0")
+
+	project, err := u.Host.GetProject(apiURL, slug)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = u.Install(apiURL, project, releases.InstallOptions{Regex: `^mod-.+\.jar$`}, pack)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(viper.GetString("meta-folder-base"), "mods", "mod"+core.MetaExtension)
+	oldMod, err := core.LoadMod(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if oldMod.FileName != r.AssetName("1.0") || oldMod.Update[u.Name]["slug"] != slug || oldMod.Update[u.Name]["tag"] != "1.0" {
+		t.Fatalf("Unexpected metadata after install: %+v", oldMod)
+	}
+	if apiURL != "" && oldMod.Update[u.Name]["api-url"] != apiURL {
+		t.Fatalf("Expected the API URL %s to be stored, got %v", apiURL, oldMod.Update[u.Name])
+	}
+
+	mod, err := core.LoadMod(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checks, err := u.CheckUpdate([]*core.Mod{&mod}, pack)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if checks[0].Error != nil || checks[0].UpdateAvailable {
+		t.Fatalf("Expected no update, got %+v", checks[0])
+	}
+
+	r.Publish("1.1")
+	checks, err = u.CheckUpdate([]*core.Mod{&mod}, pack)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if checks[0].Error != nil || !checks[0].UpdateAvailable {
+		t.Fatalf("Expected an update, got %+v", checks[0])
+	}
+	err = u.DoUpdate([]*core.Mod{&mod}, []interface{}{checks[0].CachedState})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mod.FileName != r.AssetName("1.1") || mod.Download.URL != r.AssetURL("1.1") || mod.Update[u.Name]["tag"] != "1.1" {
+		t.Errorf("Unexpected metadata after update: %+v", mod)
+	}
+
+	// Enough releases are published that the installed release is on a later page than the latest release
+	for i := 2; i <= 150; i++ {
+		r.Publish("1." + strconv.Itoa(i))
+	}
+	mod.Update[u.Name]["tag"] = "1.150"
+	if _, _, err := mod.Write(); err != nil {
+		t.Fatal(err)
+	}
+	newMod, err := core.LoadMod(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	changelogs, err := u.GetChangelogs(&oldMod, &newMod)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(changelogs) != 150 || changelogs[0].Version != "1.150" || changelogs[149].Version != "1.1" {
+		t.Errorf("Expected changelogs from 1.150 to 1.1, got %d", len(changelogs))
+	}
+}
+
+// CheckTokenScoping checks that the token configured under key is only sent to the instance at defaultApiURL, and that
+// the tokens of other instances are only sent to those instances, using request to make a request to apiURL
+func CheckTokenScoping(t *testing.T, key string, defaultApiURL string, apiURL string, tokens *Tokens, request func() error) {
+	t.Helper()
+	t.Cleanup(func() {
+		viper.Set(key+".token", nil)
+		viper.Set(key+".instances", nil)
+	})
+
+	// The token for the default instance must not be sent to other hosts named in metadata files
+	viper.Set(key+".token", "default-token")
+	if err := request(); err != nil {
+		t.Fatal(err)
+	}
+	for _, v := range tokens.Sent() {
+		if v != "" {
+			t.Errorf("Expected no token to be sent to an unconfigured host, got %q", v)
+		}
+	}
+
+	viper.Set(key+".instances", []map[string]interface{}{
+		{"url": "https://other.example.com", "token": "other-token"},
+		{"url": apiURL, "token": "instance-token"},
+	})
+	if err := request(); err != nil {
+		t.Fatal(err)
+	}
+	if sent := tokens.Sent(); len(sent) != 1 || sent[0] != "instance-token" {
+		t.Errorf("Expected the instance token to be sent, got %q", sent)
+	}
+
+	if token := core.GetAPIToken(key, defaultApiURL, defaultApiURL+"/projects/x"); token != "default-token" {
+		t.Errorf("Expected the default token for %s, got %q", defaultApiURL, token)
+	}
+}
@@ -0,0 +1,231 @@
+package releases
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/mitchellh/mapstructure"
+	"github.com/packwiz/packwiz/core"
+)
+
+// Updater is the update system for projects released on a Host
+type Updater struct {
+	// Name is the name of the update system, used for its metadata in metadata files
+	Name string
+	Host Host
+	// DefaultApiURL is used when metadata files don't specify an API URL; it is empty for hosts with a single instance
+	DefaultApiURL string
+}
+
+func (u Updater) ParseUpdate(updateUnparsed map[string]interface{}) (interface{}, error) {
+	var updateData UpdateData
+	err := mapstructure.Decode(updateUnparsed, &updateData)
+	if updateData.ApiURL == "" {
+		updateData.ApiURL = u.DefaultApiURL
+	}
+	return updateData, err
+}
+
+type cachedStateStore struct {
+	Release Release
+	Asset   Asset
+}
+
+// releaseKey identifies the releases that a file is updated from
+type releaseKey struct {
+	Slug       string
+	ApiURL     string
+	Branch     string
+	Channel    string
+	TagPattern string
+}
+
+func (u UpdateData) releaseKey() releaseKey {
+	return releaseKey{u.Slug, u.ApiURL, u.Branch, u.Channel, u.TagPattern}
+}
+
+func (u Updater) CheckUpdate(mods []*core.Mod, pack core.Pack) ([]core.UpdateCheck, error) {
+	results := make([]core.UpdateCheck, len(mods))
+	// Files added from multiple assets of the same release share the latest release, so it is only retrieved once
+	latestReleases := make(map[releaseKey]Release)
+	updating := make(map[releaseKey][]string)
+	for _, mod := range mods {
+		if rawData, ok := mod.GetParsedUpdateData(u.Name); ok {
+			data := rawData.(UpdateData)
+			updating[data.releaseKey()] = append(updating[data.releaseKey()], data.Regex)
+		}
+	}
+	var packMods []*core.Mod
+
+	for i, mod := range mods {
+		rawData, ok := mod.GetParsedUpdateData(u.Name)
+		if !ok {
+			results[i] = core.UpdateCheck{Error: errors.New("failed to parse update metadata")}
+			continue
+		}
+
+		data := rawData.(UpdateData)
+
+		newRelease, ok := latestReleases[data.releaseKey()]
+		if !ok {
+			var err error
+			newRelease, err = u.GetLatestRelease(data.ApiURL, data.Slug, data.Branch, data.Channel, data.TagPattern)
+			if err != nil {
+				results[i] = core.UpdateCheck{Error: fmt.Errorf("failed to get latest release: %v", err)}
+				continue
+			}
+			latestReleases[data.releaseKey()] = newRelease
+		}
+
+		if newRelease.TagName == data.Tag { // The latest release is the same as the installed one
+			results[i] = core.UpdateCheck{UpdateAvailable: false}
+			continue
+		}
+
+		newFile, err := MatchAsset(newRelease, data.Regex)
+		if err != nil {
+			results[i] = core.UpdateCheck{Error: err}
+			continue
+		}
+
+		// Files added from the same release are only updated if all of them can be, so they stay on the same release
+		if len(data.Siblings) > 0 {
+			if packMods == nil && !containsAll(updating[data.releaseKey()], data.Siblings) {
+				packMods, err = loadPackMods(pack)
+				if err != nil {
+					results[i] = core.UpdateCheck{Error: err}
+					continue
+				}
+			}
+			err = u.checkSiblings(data, newRelease, updating[data.releaseKey()], packMods)
+			if err != nil {
+				results[i] = core.UpdateCheck{Error: err}
+				continue
+			}
+		}
+
+		results[i] = core.UpdateCheck{
+			UpdateAvailable: true,
+			UpdateString:    mod.FileName + " -> " + newFile.Name,
+			NewFileName:     newFile.Name,
+			CachedState:     cachedStateStore{newRelease, newFile},
+		}
+	}
+
+	return results, nil
+}
+
+// checkSiblings checks that the other files added from the same release as a file can be updated to the new release
+// with it: their assets must be in the new release, and they must be updated at the same time (unless they are
+// pinned, or have been removed from the pack)
+func (u Updater) checkSiblings(data UpdateData, newRelease Release, updating []string, packMods []*core.Mod) error {
+	for _, regex := range data.Siblings {
+		if _, err := MatchAsset(newRelease, regex); err != nil {
+			return fmt.Errorf("the release has no asset for a file added together with this one (matching %s): %v", regex, err)
+		}
+		if slices.Contains(updating, regex) {
+			continue
+		}
+		for _, mod := range packMods {
+			rawData, ok := mod.GetParsedUpdateData(u.Name)
+			if !ok || mod.Pin {
+				continue
+			}
+			if sibling := rawData.(UpdateData); sibling.releaseKey() == data.releaseKey() && sibling.Regex == regex {
+				return fmt.Errorf("this file was added together with %s, so they must be updated at the same time (use --all)", mod.Name)
+			}
+		}
+	}
+	return nil
+}
+
+func containsAll(list []string, values []string) bool {
+	for _, v := range values {
+		if !slices.Contains(list, v) {
+			return false
+		}
+	}
+	return true
+}
+
+func loadPackMods(pack core.Pack) ([]*core.Mod, error) {
+	index, err := pack.LoadIndex()
+	if err != nil {
+		return nil, err
+	}
+	return index.LoadAllMods()
+}
+
+func (u Updater) DoUpdate(mods []*core.Mod, cachedState []interface{}) error {
+	for i, mod := range mods {
+		modState := cachedState[i].(cachedStateStore)
+		file := modState.Asset
+
+		hash, err := HashAsset(u.Host, file)
+		if err != nil {
+			return err
+		}
+
+		mod.FileName = file.Name
+		mod.Download = core.ModDownload{
+			URL:        file.DownloadURL,
+			HashFormat: "sha256",
+			Hash:       hash,
+		}
+		mod.Update[u.Name]["tag"] = modState.Release.TagName
+	}
+
+	return nil
+}
+
+func (u Updater) GetChangelogs(oldMod *core.Mod, newMod *core.Mod) ([]core.Changelog, error) {
+	oldRaw, ok := oldMod.GetParsedUpdateData(u.Name)
+	if !ok {
+		return nil, errors.New("failed to parse update metadata")
+	}
+	newRaw, ok := newMod.GetParsedUpdateData(u.Name)
+	if !ok {
+		return nil, errors.New("failed to parse update metadata")
+	}
+	oldData := oldRaw.(UpdateData)
+	newData := newRaw.(UpdateData)
+
+	// Releases are listed newest first; include everything from the new release until the old release, which may be
+	// on a later page
+	var changelogs []core.Changelog
+	found := false
+	for page := 1; ; page++ {
+		releases, err := u.Host.GetReleases(newData.ApiURL, newData.Slug, page)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get releases: %v", err)
+		}
+		if len(releases) == 0 {
+			break
+		}
+		for _, r := range releases {
+			if r.TagName == newData.Tag {
+				found = true
+			}
+			if !found {
+				continue
+			}
+			if r.TagName == oldData.Tag {
+				return changelogs, nil
+			}
+			if r.Draft || newData.Branch != "" && r.Branch != newData.Branch {
+				continue
+			}
+			name := r.Name
+			if name == "" {
+				name = r.TagName
+			}
+			changelogs = append(changelogs, core.Changelog{Version: name, Text: r.Body})
+		}
+	}
+	if !found {
+		return nil, fmt.Errorf("failed to find release %s", newData.Tag)
+	}
+	// Return the changelogs that were found, as the old release may have been deleted
+	return changelogs, fmt.Errorf("failed to find release %s", oldData.Tag)
+}
@@ -0,0 +1,261 @@
+package releases
+
+import (
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"testing"
+
+	"github.com/packwiz/packwiz/core"
+)
+
+// testHost is a Host serving the releases of a single project, on pages of pageSize releases
+type testHost struct {
+	releases []Release
+	pageSize int
+}
+
+func (h testHost) GetProject(string, string) (Project, error) {
+	return Project{Name: "Mod", Slug: "owner/mod"}, nil
+}
+
+func (h testHost) GetReleases(_ string, _ string, page int) ([]Release, error) {
+	start := (page - 1) * h.pageSize
+	if start >= len(h.releases) {
+		return nil, nil
+	}
+	return h.releases[start:min(start+h.pageSize, len(h.releases))], nil
+}
+
+func (h testHost) GetAsset(Asset) (*http.Response, error) {
+	return nil, errors.New("assets can't be downloaded")
+}
+
+func TestGetLatestReleaseChannels(t *testing.T) {
+	// Releases are listed newest first
+	u := Updater{Name: "test", Host: testHost{[]Release{
+		{TagName: "v3.0.0", Branch: "main", Draft: true},
+		{TagName: "v2.0.0-beta", Branch: "main", Prerelease: true},
+		{TagName: "v1.10.0", Branch: "dev"},
+		{TagName: "v1.9.0", Branch: "main"},
+	}, 10}}
+
+	tests := []struct {
+		branch     string
+		channel    string
+		tagPattern string
+		want       string
+	}{
+		// Metadata files without a channel keep tracking the latest release, including pre-releases
+		{"", "", "", "v2.0.0-beta"},
+		{"", channelPrerelease, "", "v2.0.0-beta"},
+		{"", channelRelease, "", "v1.10.0"},
+		{"main", channelRelease, "", "v1.9.0"},
+		{"", channelTagPattern, `^v1\.`, "v1.10.0"},
+	}
+	for _, tt := range tests {
+		release, err := u.GetLatestRelease("", "owner/mod", tt.branch, tt.channel, tt.tagPattern)
+		if err != nil {
+			t.Errorf("GetLatestRelease(%q, %q, %q) failed: %v", tt.branch, tt.channel, tt.tagPattern, err)
+			continue
+		}
+		if release.TagName != tt.want {
+			t.Errorf("GetLatestRelease(%q, %q, %q) = %s, want %s", tt.branch, tt.channel, tt.tagPattern, release.TagName, tt.want)
+		}
+	}
+}
+
+func loadTestMod(t *testing.T, tag string) *core.Mod {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "mod.pw.toml")
+	err := os.WriteFile(path, []byte(`name = "Mod"
+filename = "mod-`+tag+`.jar"
+[download]
+url = "https://example.com/mod-`+tag+`.jar"
+hash-format = "sha256"
+hash = "abc"
+[update.test]
+slug = "owner/mod"
+tag = "`+tag+`"
+branch = "main"
+regex = "^mod-.+\\.jar$"
+`), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mod, err := core.LoadMod(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &mod
+}
+
+func TestGetChangelogs(t *testing.T) {
+	u := Updater{Name: "test", Host: testHost{[]Release{
+		{TagName: "v6", Branch: "main"},
+		{TagName: "v5", Branch: "main", Draft: true},
+		{TagName: "v4", Branch: "dev"},
+		{TagName: "v3", Name: "Version 3", Branch: "main"},
+		{TagName: "v2", Branch: "main"},
+		{TagName: "v1", Branch: "main"},
+	}, 2}}
+	core.Updaters["test"] = u
+	t.Cleanup(func() {
+		delete(core.Updaters, "test")
+	})
+
+	tests := []struct {
+		oldTag  string
+		newTag  string
+		want    []string
+		wantErr bool
+	}{
+		// Drafts and releases from other branches are skipped
+		{"v1", "v6", []string{"v6", "Version 3", "v2"}, false},
+		{"v2", "v3", []string{"Version 3"}, false},
+		// Changelogs are returned when the old release can't be found, as it may have been deleted
+		{"v0", "v3", []string{"Version 3", "v2", "v1"}, true},
+		{"v1", "v7", nil, true},
+	}
+	for _, tt := range tests {
+		changelogs, err := u.GetChangelogs(loadTestMod(t, tt.oldTag), loadTestMod(t, tt.newTag))
+		if (err != nil) != tt.wantErr {
+			t.Errorf("GetChangelogs(%s, %s) error = %v, wantErr %v", tt.oldTag, tt.newTag, err, tt.wantErr)
+		}
+		var versions []string
+		for _, v := range changelogs {
+			versions = append(versions, v.Version)
+		}
+		if !slices.Equal(versions, tt.want) {
+			t.Errorf("GetChangelogs(%s, %s) = %v, want %v", tt.oldTag, tt.newTag, versions, tt.want)
+		}
+	}
+}
+
+func TestParseUpdate(t *testing.T) {
+	u := Updater{Name: "test", DefaultApiURL: "https://example.com/api"}
+	tests := []struct {
+		data map[string]interface{}
+		want string
+	}{
+		{map[string]interface{}{"slug": "owner/mod", "tag": "v1"}, "https://example.com/api/owner/mod"},
+		{map[string]interface{}{"slug": "owner/mod", "tag": "v1", "api-url": "https://other.example.com/api"}, "https://other.example.com/api/owner/mod"},
+	}
+	for _, tt := range tests {
+		data, err := u.ParseUpdate(tt.data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if id := data.(UpdateData).GetProjectID(); id != tt.want {
+			t.Errorf("GetProjectID() = %s, want %s", id, tt.want)
+		}
+	}
+
+	// Hosts with a single instance don't store an API URL
+	data, err := Updater{Name: "test"}.ParseUpdate(map[string]interface{}{"slug": "owner/mod", "tag": "v1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id := data.(UpdateData).GetProjectID(); id != "owner/mod" {
+		t.Errorf("GetProjectID() = %s, want owner/mod", id)
+	}
+}
+
+// writeSiblingMod writes a metadata file for one of two assets (client and server) added from the same release
+func writeSiblingMod(t *testing.T, dir string, side string, other string, pin bool) string {
+	t.Helper()
+	path := filepath.Join(dir, "mod-"+side+".pw.toml")
+	err := os.WriteFile(path, []byte(`name = "Mod (`+side+`)"
+filename = "mod-v1-`+side+`.jar"
+pin = `+strconv.FormatBool(pin)+`
+[download]
+url = "https://example.com/mod-v1-`+side+`.jar"
+hash-format = "sha256"
+hash = "abc"
+[update.test]
+slug = "owner/mod"
+tag = "v1"
+regex = "^mod-`+side+`\\.jar$"
+siblings = ["^mod-`+other+`\\.jar$"]
+`), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestCheckUpdateSiblings(t *testing.T) {
+	release := func(tag string, assets ...string) Release {
+		r := Release{TagName: tag}
+		for _, v := range assets {
+			r.Assets = append(r.Assets, Asset{Name: v})
+		}
+		return r
+	}
+	tests := []struct {
+		name string
+		// The latest release; mod-client.jar and mod-server.jar were added from v1
+		latest Release
+		// Whether only the client file is checked, and whether the server file is pinned
+		clientOnly bool
+		pinned     bool
+		want       []bool
+	}{
+		{"both updated", release("v2", "mod-client.jar", "mod-server.jar"), false, false, []bool{true, true}},
+		{"asset removed", release("v2", "mod-client.jar"), false, false, []bool{false, false}},
+		{"sibling not updated", release("v2", "mod-client.jar", "mod-server.jar"), true, false, []bool{false}},
+		{"sibling pinned", release("v2", "mod-client.jar", "mod-server.jar"), true, true, []bool{true}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := Updater{Name: "test", Host: testHost{[]Release{tt.latest}, 10}}
+			core.Updaters["test"] = u
+			t.Cleanup(func() {
+				delete(core.Updaters, "test")
+			})
+
+			dir := t.TempDir()
+			paths := []string{writeSiblingMod(t, dir, "client", "server", false), writeSiblingMod(t, dir, "server", "client", tt.pinned)}
+			indexFile := filepath.Join(dir, "index.toml")
+			err := os.WriteFile(indexFile, []byte(`hash-format = "sha256"
+[[files]]
+file = "mod-client.pw.toml"
+hash = ""
+metafile = true
+[[files]]
+file = "mod-server.pw.toml"
+hash = ""
+metafile = true
+`), 0644)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var pack core.Pack
+			pack.Index.File = indexFile
+
+			if tt.clientOnly {
+				paths = paths[:1]
+			}
+			var mods []*core.Mod
+			for _, path := range paths {
+				mod, err := core.LoadMod(path)
+				if err != nil {
+					t.Fatal(err)
+				}
+				mods = append(mods, &mod)
+			}
+			checks, err := u.CheckUpdate(mods, pack)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for i, check := range checks {
+				if check.UpdateAvailable != tt.want[i] || (check.Error == nil) != tt.want[i] {
+					t.Errorf("CheckUpdate(%s) = %+v, want update %v", mods[i].Name, check, tt.want[i])
+				}
+			}
+		})
+	}
+}
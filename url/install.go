@@ -1,7 +1,9 @@
 package url
 
 import (
+	"errors"
 	"fmt"
+	"github.com/dlclark/regexp2"
 	"github.com/packwiz/packwiz/core"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
@@ -56,12 +58,18 @@ var installCmd = &cobra.Command{
 			}
 		}
 
-		hash, err := getHash(args[1])
+		hash, headers, err := getHash(args[1])
 		if err != nil {
 			fmt.Println("Failed to retrieve SHA256 hash for file", err)
 			os.Exit(1)
 		}
 
+		updateData, err := getUpdateData(cmd, args[1], headers)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+
 		index, err := pack.LoadIndex()
 		if err != nil {
 			fmt.Println(err)
@@ -79,6 +87,9 @@ var installCmd = &cobra.Command{
 				Hash:       hash,
 			},
 		}
+		if updateData != nil {
+			modMeta.Update = map[string]map[string]interface{}{"url": updateData}
+		}
 
 		folder := viper.GetString("meta-folder")
 		if folder == "" {
@@ -123,32 +134,91 @@ var installCmd = &cobra.Command{
 		fmt.Printf("Successfully added %s (%s) from: %s\n", args[0], destPath, args[1])
 	}}
 
-func getHash(url string) (string, error) {
+// getUpdateData returns the update metadata to store for a file, or nil if it shouldn't be updated
+func getUpdateData(cmd *cobra.Command, fileURL string, headers cachingHeaders) (map[string]interface{}, error) {
+	trackChanges, err := cmd.Flags().GetBool("update")
+	if err != nil {
+		return nil, err
+	}
+	var data urlUpdateData
+	data.URLTemplate, err = cmd.Flags().GetString("url-template")
+	if err != nil {
+		return nil, err
+	}
+	data.Version, err = cmd.Flags().GetString("version")
+	if err != nil {
+		return nil, err
+	}
+	data.VersionsURL, err = cmd.Flags().GetString("versions-url")
+	if err != nil {
+		return nil, err
+	}
+	data.VersionRegex, err = cmd.Flags().GetString("version-regex")
+	if err != nil {
+		return nil, err
+	}
+
+	if data.URLTemplate != "" {
+		if data.Version == "" || data.VersionsURL == "" || data.VersionRegex == "" {
+			return nil, errors.New("--url-template requires --version, --versions-url and --version-regex to be set")
+		}
+		if !strings.Contains(data.URLTemplate, versionPlaceholder) {
+			return nil, fmt.Errorf("the URL template must contain %s", versionPlaceholder)
+		}
+		if data.getURL(data.Version) != fileURL {
+			return nil, fmt.Errorf("the URL template doesn't match the URL for version %s (%s)", data.Version, data.getURL(data.Version))
+		}
+		_, err = regexp2.Compile(data.VersionRegex, 0)
+		if err != nil {
+			return nil, fmt.Errorf("invalid version regex: %v", err)
+		}
+		return data.ToMap()
+	}
+	if data.Version != "" || data.VersionsURL != "" || data.VersionRegex != "" {
+		return nil, errors.New("--version, --versions-url and --version-regex can only be used with --url-template")
+	}
+	if !trackChanges {
+		return nil, nil
+	}
+	if headers.ETag == "" && headers.LastModified == "" {
+		return nil, errors.New("the server doesn't provide an ETag or Last-Modified header for this URL, so changes to the file can't be found")
+	}
+	data.ETag = headers.ETag
+	data.LastModified = headers.LastModified
+	return data.ToMap()
+}
+
+func getHash(url string) (string, cachingHeaders, error) {
 	mainHasher, err := core.GetHashImpl("sha256")
 	if err != nil {
-		return "", err
+		return "", cachingHeaders{}, err
 	}
 	resp, err := core.GetWithUA(url, "application/octet-stream")
 	if err != nil {
-		return "", err
+		return "", cachingHeaders{}, err
 	}
 
 	defer resp.Body.Close()
 	if resp.StatusCode != 200 {
-		return "", fmt.Errorf("failed to download: unexpected response status: %v", resp.Status)
+		return "", cachingHeaders{}, fmt.Errorf("failed to download: unexpected response status: %v", resp.Status)
 	}
 
 	_, err = io.Copy(mainHasher, resp.Body)
 	if err != nil {
-		return "", err
+		return "", cachingHeaders{}, err
 	}
 
-	return mainHasher.HashToString(mainHasher.Sum(nil)), nil
+	return mainHasher.HashToString(mainHasher.Sum(nil)), getCachingHeaders(resp), nil
 }
 
 func init() {
 	urlCmd.AddCommand(installCmd)
 
 	installCmd.Flags().Bool("force", false, "Add a file even if the download URL is supported by packwiz in an alternative command (which may support dependencies and updates)")
+	installCmd.Flags().Bool("update", false, "Store the ETag/Last-Modified headers of the URL, so that packwiz update can find changes to the file")
+	installCmd.Flags().String("url-template", "", "A download URL containing "+versionPlaceholder+" to be replaced with new versions, so that packwiz update can find new versions")
+	installCmd.Flags().String("version", "", "The version of the file at the URL, for use with --url-template")
+	installCmd.Flags().String("versions-url", "", "A page listing the available versions, for use with --url-template")
+	installCmd.Flags().String("version-regex", "", "The regular expression matching versions on the versions page (using the first group, if there is one), for use with --url-template")
 	installCmd.Flags().String("meta-name", "", "Filename to use for the created metadata file (defaults to a name generated from the name you supply)")
 }
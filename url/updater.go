@@ -0,0 +1,245 @@
+package url
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/dlclark/regexp2"
+	"github.com/mitchellh/mapstructure"
+	"github.com/packwiz/packwiz/core"
+	"github.com/unascribed/FlexVer/go/flexver"
+)
+
+// versionPlaceholder is replaced with the version in URL templates
+const versionPlaceholder = "{version}"
+
+type urlUpdateData struct {
+	// ETag and LastModified are the caching headers of the download URL when the file was added or last updated
+	ETag         string `mapstructure:"etag,omitempty"`
+	LastModified string `mapstructure:"last-modified,omitempty"`
+	// URLTemplate is a download URL containing a {version} placeholder; when it is set, new versions are found by
+	// matching VersionRegex against the page at VersionsURL instead of using the caching headers
+	URLTemplate  string `mapstructure:"url-template,omitempty"`
+	Version      string `mapstructure:"version,omitempty"`
+	VersionsURL  string `mapstructure:"versions-url,omitempty"`
+	VersionRegex string `mapstructure:"version-regex,omitempty"`
+}
+
+func (u urlUpdateData) ToMap() (map[string]interface{}, error) {
+	newMap := make(map[string]interface{})
+	err := mapstructure.Decode(u, &newMap)
+	return newMap, err
+}
+
+func (u urlUpdateData) getURL(version string) string {
+	return strings.ReplaceAll(u.URLTemplate, versionPlaceholder, version)
+}
+
+// getLatestVersion finds the highest version (using FlexVer ordering) matching the version regex on the versions page;
+// the first capturing group of the regex is used as the version if there is one, otherwise the whole match
+func (u urlUpdateData) getLatestVersion() (string, error) {
+	expr, err := regexp2.Compile(u.VersionRegex, 0)
+	if err != nil {
+		return "", fmt.Errorf("invalid version regex: %v", err)
+	}
+
+	resp, err := core.GetWithUA(u.VersionsURL, "*/*")
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != 200 {
+		return "", fmt.Errorf("failed to retrieve versions: unexpected response status: %v", resp.Status)
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+
+	var latest string
+	match, err := expr.FindStringMatch(string(body))
+	for match != nil && err == nil {
+		version := match.String()
+		if groups := match.Groups(); len(groups) > 1 {
+			version = groups[1].String()
+		}
+		if version != "" && (latest == "" || flexver.Less(latest, version)) {
+			latest = version
+		}
+		match, err = expr.FindNextMatch(match)
+	}
+	if err != nil {
+		return "", err
+	}
+	if latest == "" {
+		return "", errors.New("no versions matching the version regex were found")
+	}
+	return latest, nil
+}
+
+// cachingHeaders stores the headers used to find whether the file at a URL has changed
+type cachingHeaders struct {
+	ETag         string
+	LastModified string
+}
+
+func getCachingHeaders(resp *http.Response) cachingHeaders {
+	return cachingHeaders{
+		ETag:         resp.Header.Get("ETag"),
+		LastModified: resp.Header.Get("Last-Modified"),
+	}
+}
+
+// hasChanged uses a conditional request to find whether the file at a URL has changed since the caching headers in the
+// update data were stored
+func (u urlUpdateData) hasChanged(fileURL string) (bool, error) {
+	req, err := http.NewRequest("GET", fileURL, nil)
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("User-Agent", core.UserAgent)
+	req.Header.Set("Accept", "application/octet-stream")
+	if u.ETag != "" {
+		req.Header.Set("If-None-Match", u.ETag)
+	}
+	if u.LastModified != "" {
+		req.Header.Set("If-Modified-Since", u.LastModified)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return false, err
+	}
+	// Only the headers are needed
+	_ = resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotModified {
+		return false, nil
+	}
+	if resp.StatusCode != 200 {
+		return false, fmt.Errorf("unexpected response status: %v", resp.Status)
+	}
+	// Servers may ignore conditional requests, so compare the headers as well
+	headers := getCachingHeaders(resp)
+	if u.ETag != "" && headers.ETag != "" {
+		return headers.ETag != u.ETag, nil
+	}
+	if u.LastModified != "" && headers.LastModified != "" {
+		return headers.LastModified != u.LastModified, nil
+	}
+	return false, errors.New("the server no longer provides an ETag or Last-Modified header")
+}
+
+type urlUpdater struct{}
+
+func (u urlUpdater) ParseUpdate(updateUnparsed map[string]interface{}) (interface{}, error) {
+	var updateData urlUpdateData
+	err := mapstructure.Decode(updateUnparsed, &updateData)
+	return updateData, err
+}
+
+type cachedStateStore struct {
+	URL     string
+	Version string
+}
+
+func (u urlUpdater) CheckUpdate(mods []*core.Mod, pack core.Pack) ([]core.UpdateCheck, error) {
+	results := make([]core.UpdateCheck, len(mods))
+
+	for i, mod := range mods {
+		rawData, ok := mod.GetParsedUpdateData("url")
+		if !ok {
+			results[i] = core.UpdateCheck{Error: errors.New("failed to parse update metadata")}
+			continue
+		}
+		data := rawData.(urlUpdateData)
+
+		if data.URLTemplate != "" {
+			newVersion, err := data.getLatestVersion()
+			if err != nil {
+				results[i] = core.UpdateCheck{Error: fmt.Errorf("failed to get latest version: %v", err)}
+				continue
+			}
+			// Only update to newer versions, in case the installed version was chosen manually
+			if newVersion == data.Version || !flexver.Less(data.Version, newVersion) {
+				results[i] = core.UpdateCheck{UpdateAvailable: false}
+				continue
+			}
+			newURL := data.getURL(newVersion)
+			results[i] = core.UpdateCheck{
+				UpdateAvailable: true,
+				UpdateString:    data.Version + " -> " + newVersion,
+				NewFileName:     fileNameFromURL(newURL),
+				CachedState:     cachedStateStore{newURL, newVersion},
+			}
+			continue
+		}
+
+		if data.ETag == "" && data.LastModified == "" {
+			results[i] = core.UpdateCheck{Error: errors.New("update metadata doesn't have an ETag, Last-Modified date or URL template")}
+			continue
+		}
+		changed, err := data.hasChanged(mod.Download.URL)
+		if err != nil {
+			results[i] = core.UpdateCheck{Error: fmt.Errorf("failed to check for changes: %v", err)}
+			continue
+		}
+		if !changed {
+			results[i] = core.UpdateCheck{UpdateAvailable: false}
+			continue
+		}
+		results[i] = core.UpdateCheck{
+			UpdateAvailable: true,
+			UpdateString:    mod.FileName + " (file changed)",
+			NewFileName:     mod.FileName,
+			CachedState:     cachedStateStore{mod.Download.URL, ""},
+		}
+	}
+
+	return results, nil
+}
+
+func (u urlUpdater) DoUpdate(mods []*core.Mod, cachedState []interface{}) error {
+	for i, mod := range mods {
+		modState := cachedState[i].(cachedStateStore)
+
+		hash, headers, err := getHash(modState.URL)
+		if err != nil {
+			return err
+		}
+
+		mod.Download = core.ModDownload{
+			URL:        modState.URL,
+			HashFormat: "sha256",
+			Hash:       hash,
+		}
+		if modState.Version != "" {
+			mod.FileName = fileNameFromURL(modState.URL)
+			mod.Update["url"]["version"] = modState.Version
+		} else {
+			setOrDelete(mod.Update["url"], "etag", headers.ETag)
+			setOrDelete(mod.Update["url"], "last-modified", headers.LastModified)
+		}
+	}
+
+	return nil
+}
+
+func fileNameFromURL(rawURL string) string {
+	if parsed, err := url.Parse(rawURL); err == nil {
+		return path.Base(parsed.Path)
+	}
+	return path.Base(rawURL)
+}
+
+func setOrDelete(m map[string]interface{}, key string, value string) {
+	if value == "" {
+		delete(m, key)
+	} else {
+		m[key] = value
+	}
+}
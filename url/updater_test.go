@@ -0,0 +1,104 @@
+package url
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHasChanged(t *testing.T) {
+	etag := `"v1"`
+	lastModified := "Mon, 02 Jan 2006 15:04:05 GMT"
+	// ignoreConditional makes the server ignore conditional requests, so hasChanged must compare the headers itself
+	ignoreConditional := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/etag":
+			w.Header().Set("ETag", etag)
+			if !ignoreConditional && r.Header.Get("If-None-Match") == etag {
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+		case "/last-modified":
+			w.Header().Set("Last-Modified", lastModified)
+			if !ignoreConditional && r.Header.Get("If-Modified-Since") == lastModified {
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+		case "/plain":
+		default:
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte("file"))
+	}))
+	t.Cleanup(server.Close)
+
+	tests := []struct {
+		path    string
+		data    urlUpdateData
+		want    bool
+		wantErr bool
+	}{
+		{"/etag", urlUpdateData{ETag: `"v1"`}, false, false},
+		{"/etag", urlUpdateData{ETag: `"v0"`}, true, false},
+		{"/last-modified", urlUpdateData{LastModified: lastModified}, false, false},
+		{"/last-modified", urlUpdateData{LastModified: "Sun, 01 Jan 2006 15:04:05 GMT"}, true, false},
+		{"/plain", urlUpdateData{ETag: `"v1"`}, false, true},
+		{"/missing", urlUpdateData{ETag: `"v1"`}, false, true},
+	}
+	for _, ignore := range []bool{false, true} {
+		ignoreConditional = ignore
+		for _, tt := range tests {
+			got, err := tt.data.hasChanged(server.URL + tt.path)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("hasChanged(%s, %+v) error = %v, wantErr %v (ignoring conditional requests: %v)", tt.path, tt.data, err, tt.wantErr, ignore)
+				continue
+			}
+			if got != tt.want {
+				t.Errorf("hasChanged(%s, %+v) = %v, want %v (ignoring conditional requests: %v)", tt.path, tt.data, got, tt.want, ignore)
+			}
+		}
+	}
+}
+
+func TestGetLatestVersion(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/versions" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = fmt.Fprint(w, `<a href="mod-1.9.0.jar">mod-1.9.0.jar</a>
+<a href="mod-1.10.0.jar">mod-1.10.0.jar</a>
+<a href="mod-1.2.0.jar">mod-1.2.0.jar</a>
+<a href="other-2.0.0.jar">other-2.0.0.jar</a>`)
+	}))
+	t.Cleanup(server.Close)
+
+	tests := []struct {
+		versionsPath string
+		regex        string
+		want         string
+		wantErr      bool
+	}{
+		// Versions are compared with FlexVer rather than as strings
+		{"/versions", `mod-([0-9.]+)\.jar`, "1.10.0", false},
+		// Without a capturing group, the whole match is used
+		{"/versions", `(?<=mod-)[0-9.]+(?=\.jar)`, "1.10.0", false},
+		{"/versions", `missing-([0-9.]+)\.jar`, "", true},
+		{"/versions", `mod-([0-9.]+`, "", true},
+		{"/missing", `mod-([0-9.]+)\.jar`, "", true},
+	}
+	for _, tt := range tests {
+		data := urlUpdateData{VersionsURL: server.URL + tt.versionsPath, VersionRegex: tt.regex}
+		got, err := data.getLatestVersion()
+		if (err != nil) != tt.wantErr {
+			t.Errorf("getLatestVersion(%s, %s) error = %v, wantErr %v", tt.versionsPath, tt.regex, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("getLatestVersion(%s, %s) = %q, want %q", tt.versionsPath, tt.regex, got, tt.want)
+		}
+	}
+}
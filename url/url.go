@@ -2,6 +2,7 @@ package url
 
 import (
 	"github.com/packwiz/packwiz/cmd"
+	"github.com/packwiz/packwiz/core"
 	"github.com/spf13/cobra"
 )
 
@@ -12,4 +13,5 @@ var urlCmd = &cobra.Command{
 
 func init() {
 	cmd.Add(urlCmd)
+	core.Updaters["url"] = urlUpdater{}
 }